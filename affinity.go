@@ -0,0 +1,191 @@
+package crawlrate
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// replicas is the number of points each proxy is given on the hash ring. More
+// points spread the keywords more evenly across the proxies.
+const replicas = 160
+
+// WithAffinity assigns keywords to proxies by consistent hashing rather than by
+// their position in the keyword list, so a keyword keeps its proxy from one run to
+// the next and only a small fraction of the keywords move when the keyword or
+// proxy lists change. A proxy is never given more keywords than the pulse has
+// connections for; the keywords that hash to a full proxy move on to the next
+// proxy around the ring.
+func WithAffinity() Option {
+	return func(o *options) {
+		o.affinity = true
+	}
+}
+
+// placement is a keyword placed on a connection of a proxy in a row of the plan.
+type placement struct {
+	row, keyword int
+	cell
+}
+
+// assignFunc places the keywords into the rows of a plan, given the number of
+// keywords each row holds and the number of connections available for each proxy in
+// each row, returning the placements and the keywords left without one.
+type assignFunc func(keywords []Keyword, counts []int, volumes [][]int, lastRow LastRowStrategy) ([]placement, []Keyword)
+
+// assigner returns how the options place keywords onto the proxies.
+func (o *options) assigner(proxies []Proxy) assignFunc {
+	if o.affinity {
+		return newRing(proxies).assign
+	}
+	return byPosition
+}
+
+// byPosition places the keywords in the order they are given, shaping partial rows
+// with the last row strategy.
+func byPosition(keywords []Keyword, counts []int, volumes [][]int, lastRow LastRowStrategy) ([]placement, []Keyword) {
+	var ps []placement
+	for row, count := range counts {
+		for _, c := range lastRow.cells(count, volumes[row]) {
+			ps = append(ps, placement{row, len(ps), c})
+		}
+	}
+
+	var unscheduled []Keyword
+	if len(keywords) > len(ps) {
+		unscheduled = make([]Keyword, len(keywords)-len(ps))
+		copy(unscheduled, keywords[len(ps):])
+	}
+	return ps, unscheduled
+}
+
+// assign places each keyword on the earliest free connection of the proxy it hashes
+// to. Rows still hold the same number of keywords, but as the proxies are chosen by
+// the keywords the last row strategy does not apply.
+func (r *ring) assign(keywords []Keyword, counts []int, volumes [][]int, lastRow LastRowStrategy) ([]placement, []Keyword) {
+	// The connections of each proxy, in time order, in every row that holds
+	// keywords.
+	type slot struct {
+		row, conn int
+	}
+	free := make([][]slot, r.size)
+	quota := make([]int, len(counts))
+	var remaining int
+	for row, count := range counts {
+		if count == 0 {
+			continue
+		}
+		for proxy, volume := range volumes[row] {
+			for conn := 0; conn < volume; conn++ {
+				free[proxy] = append(free[proxy], slot{row, conn})
+			}
+		}
+		quota[row] = count
+		remaining += count
+	}
+
+	// available drops the connections of rows that are already full, reporting
+	// whether the proxy has any connections left.
+	available := func(proxy int) bool {
+		for len(free[proxy]) > 0 && quota[free[proxy][0].row] == 0 {
+			free[proxy] = free[proxy][1:]
+		}
+		return len(free[proxy]) > 0
+	}
+
+	var ps []placement
+	var unscheduled []Keyword
+	for k, keyword := range keywords {
+		proxy := -1
+		if remaining > 0 {
+			proxy = r.lookup(keyword.Text, available)
+		}
+		if proxy < 0 {
+			unscheduled = append(unscheduled, keyword)
+			continue
+		}
+
+		s := free[proxy][0]
+		free[proxy] = free[proxy][1:]
+		quota[s.row]--
+		remaining--
+		ps = append(ps, placement{s.row, k, cell{proxy, s.conn}})
+	}
+	return ps, unscheduled
+}
+
+// ring is a consistent hash ring of proxies.
+type ring struct {
+	hashes  []uint64
+	proxies []int
+	size    int // Number of proxies on the ring
+}
+
+func newRing(proxies []Proxy) *ring {
+	r := &ring{
+		hashes:  make([]uint64, 0, len(proxies)*replicas),
+		proxies: make([]int, 0, len(proxies)*replicas),
+		size:    len(proxies),
+	}
+
+	type point struct {
+		hash  uint64
+		proxy int
+	}
+	points := make([]point, 0, len(proxies)*replicas)
+	for proxy, p := range proxies {
+		for i := 0; i < replicas; i++ {
+			points = append(points, point{hash(p.String() + "#" + strconv.Itoa(i)), proxy})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].hash < points[j].hash
+	})
+
+	for _, p := range points {
+		r.hashes = append(r.hashes, p.hash)
+		r.proxies = append(r.proxies, p.proxy)
+	}
+	return r
+}
+
+// lookup returns the first proxy clockwise of key on the ring that accepts the
+// key, or -1 if no proxy does.
+func (r *ring) lookup(key string, accept func(proxy int) bool) int {
+	if len(r.hashes) == 0 {
+		return -1
+	}
+
+	h := hash(key)
+	start := sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= h
+	})
+
+	tried := make(map[int]bool)
+	for i := 0; i < len(r.hashes); i++ {
+		proxy := r.proxies[(start+i)%len(r.hashes)]
+		if tried[proxy] {
+			continue
+		}
+		if accept(proxy) {
+			return proxy
+		}
+		tried[proxy] = true
+	}
+	return -1
+}
+
+// hash returns the position of s on the ring. FNV alone clusters similar strings,
+// such as the addresses of a block of proxies, so its result is finalised with the
+// murmur3 mixer to spread them around the ring.
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,92 @@
+package crawlrate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// assignments maps each keyword of the plan to its proxy.
+func assignments(cp CrawlPlan) map[string]string {
+	m := make(map[string]string)
+	for _, r := range cp {
+		m[r.Keyword] = r.Proxy.String()
+	}
+	return m
+}
+
+func Test_Affinity(t *testing.T) {
+	p := &Pulse{Volume: 200, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}
+	keywords := generateKeywords("keyword-", 1000)
+
+	cp, err := New(keywords, generateProxies("127.0.0.", 10), p, WithAffinity())
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+	before := assignments(cp)
+	if len(before) != len(keywords) {
+		t.Errorf("Expected %d keywords scheduled. Got: %d\n", len(keywords), len(before))
+	}
+
+	// The keywords are spread across every proxy
+	load := make(map[string]int)
+	for _, v := range before {
+		load[v]++
+	}
+	if len(load) != 10 {
+		t.Errorf("Expected keywords on 10 proxies. Got: %d\n", len(load))
+	}
+
+	// The same inputs give the same assignments
+	again, _ := New(keywords, generateProxies("127.0.0.", 10), p, WithAffinity())
+	for k, v := range assignments(again) {
+		if before[k] != v {
+			t.Errorf("Error test: %s expected %s. Got: %s\n", k, before[k], v)
+		}
+	}
+
+	// Adding a keyword leaves the others where they were
+	more, _ := New(append(generateKeywords("keyword-", 1000), Keyword{Text: "keyword-new"}), generateProxies("127.0.0.", 10), p, WithAffinity())
+	for k, v := range assignments(more) {
+		if k != "keyword-new" && before[k] != v {
+			t.Errorf("Error test: %s moved from %s to %s\n", k, before[k], v)
+		}
+	}
+
+	// Adding a proxy moves only a small fraction of the keywords
+	added, _ := New(keywords, generateProxies("127.0.0.", 11), p, WithAffinity())
+	var moved int
+	for k, v := range assignments(added) {
+		if before[k] != v {
+			moved++
+		}
+	}
+	if moved > len(keywords)/5 {
+		t.Errorf("Expected fewer than %d keywords to move. Got: %d\n", len(keywords)/5, moved)
+	}
+}
+
+func Test_AffinityCapacity(t *testing.T) {
+	p := &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}
+
+	cp, err := New(generateKeywords("keyword-", 14), generateProxies("127.0.0.", 3), p, WithAffinity())
+	if !errors.Is(err, ErrUnscheduled) {
+		t.Fatalf("Expected unscheduled keywords. Got: %v\n", err)
+	}
+	var unscheduled *UnscheduledError
+	errors.As(err, &unscheduled)
+	if len(cp) != 12 || len(unscheduled.Keywords) != 2 {
+		t.Errorf("Expected 12 rules and 2 unscheduled keywords. Got: %d %d\n", len(cp), len(unscheduled.Keywords))
+	}
+
+	// No proxy connection is used twice in a tick
+	used := make(map[string]bool)
+	for k, r := range cp {
+		key := fmt.Sprintf("%s/%s/%d", r.Time, r.Proxy, r.Conn)
+		if used[key] {
+			t.Errorf("Error test: %d connection %d of %s used twice at %s\n", k, r.Conn, r.Proxy, r.Time)
+		}
+		used[key] = true
+	}
+}
@@ -3,6 +3,16 @@
 Usage:
 	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="duration" --timePeriod=3600s
 	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="connections" --maximumConnections=5
+	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="throughput" --throughput=10
+	crawlplan --keywords="./keywords.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="proxies" --maximumConnections=5 --timePeriod=3600s
+	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="duration" --timePeriod=3600s --explain
+	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="duration" --timePeriod=3600s --jitter=10s --seed=42
+	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="duration" --timePeriod=3600s --horizon=24h
+	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="duration" --timePeriod=3600s --targets="google.co.uk/en-GB/desktop,google.com/en-US/mobile"
+	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="duration" --timePeriod=3600s --cooldownRequests=50 --cooldownRest=10m
+	crawlplan --keywords="./keywords.txt" --proxies="./proxy-ranges.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="connections" --maximumConnections=5 --rangeLimit=256 --rangeSample --seed=42
+	crawlplan --keywords="./keywords.txt" --proxies="./proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="connections" --maximumConnections=5 --subnetLimit=10 --subnetV4Prefix=24 --subnetV6Prefix=48
+	crawlplan --keywords="./regional-keywords.txt" --proxies="./regional-proxies.txt" --avgJobRuntime=60s --minimumDelay=60s --algorithm="connections" --maximumConnections=5
 
 */
 package main
@@ -10,13 +20,14 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"sort"
-	"errors"
+	"strings"
 	"time"
 	"text/tabwriter"
 
@@ -25,21 +36,45 @@ import (
 
 var (
 	debug 		  *bool = flag.Bool("debug", false, "Switch on debug mode")
+	explain       *bool = flag.Bool("explain", false, "Explain how the pulse was derived instead of printing the crawl plan")
 	keywordFile   *string = flag.String("keywords", "", "Keyword file")
-	proxyFile     *string = flag.String("proxies", "", "proxy file")
-	avgJobRuntime *time.Duration = flag.Duration("avgJobRuntime", time.Duration(60) * time.Second, "Average job runtime in seconds. Defaults to 60")
-	minimumDelay  *time.Duration = flag.Duration("minimumDelay", time.Duration(0) * time.Second, "A minimum delay between jobs in seconds. Defaults to 0")
-	algo          *string = flag.String("algorithm", "duration", "algorithm used [duration|connections]")
+	proxyFile     *string = flag.String("proxies", "", "proxy file, one address, host:port, URL or range of addresses per line")
+	avgJobRuntime *time.Duration = flag.Duration("avgJobRuntime", time.Duration(60) * time.Second, "Average job runtime, to millisecond resolution e.g. 450ms. Defaults to 60s")
+	minimumDelay  *time.Duration = flag.Duration("minimumDelay", time.Duration(0) * time.Second, "A minimum delay between jobs, to millisecond resolution. Defaults to 0s")
+	algo          *string = flag.String("algorithm", "duration", "algorithm used ["+strings.Join(crawlrate.Algorithms(), "|")+"]")
 
-	timePeriod *time.Duration = flag.Duration("timePeriod", time.Duration(3600) * time.Second, "The total maximum duration as used by the duration algorithm. Defaults to 3600 seconds (1hr)")
-	maximumConnections *int = flag.Int("maximumConnections", 5, "The total maximum connections as used by the connections algorithm. Defaults to ")
+	timePeriod *time.Duration = flag.Duration("timePeriod", time.Duration(3600) * time.Second, "The total maximum duration as used by the duration and proxies algorithms. Defaults to 3600 seconds (1hr)")
+	maximumConnections *int = flag.Int("maximumConnections", 5, "The total maximum connections as used by the connections and proxies algorithms. Defaults to ")
+	weighting  *string = flag.String("weighting", "bottom", "The weighting of the crawl plan [bottom|top|even]. Defaults to bottom")
+	lastRow    *string = flag.String("lastRow", "cluster", "How the partial row of the crawl plan is distributed [cluster|spread]. Defaults to cluster")
+	throughput *int = flag.Int("throughput", 10, "The target keywords per minute as used by the throughput algorithm. Defaults to 10")
+	jitter     *time.Duration = flag.Duration("jitter", time.Duration(0) * time.Second, "The maximum random offset of each job within its tick, to millisecond resolution. Defaults to 0s")
+	seed       *int64 = flag.Int64("seed", 0, "The seed of the jitter and of sampled proxy ranges, so the same inputs reproduce the same plan. Defaults to 0")
+	horizon    *time.Duration = flag.Duration("horizon", time.Duration(0) * time.Second, "Plan every recrawl of the keywords over this horizon e.g. 24h, using the intervals in the keyword file. Defaults to 0s, a single pass")
+	targets    *string = flag.String("targets", "", "Comma separated search targets to crawl every keyword against, each engine/locale/device e.g. google.co.uk/en-GB/desktop,google.com/en-US/mobile. Defaults to none")
+	cooldownRequests *int = flag.Int("cooldownRequests", 0, "The number of keywords a proxy serves before it must rest. Defaults to 0, no cooldown")
+	cooldownRest     *time.Duration = flag.Duration("cooldownRest", time.Duration(0) * time.Second, "How long a proxy rests for once it has served cooldownRequests keywords. Defaults to 0s")
+	rangeLimit  *int = flag.Int("rangeLimit", crawlrate.DefaultRangeLimit, "The most proxies a range of addresses in the proxy file expands into. Defaults to "+fmt.Sprint(crawlrate.DefaultRangeLimit))
+	rangeSample *bool = flag.Bool("rangeSample", false, "Sample rangeLimit addresses from a larger range, seeded by seed, rather than fail. Defaults to false")
+	subnetLimit    *int = flag.Int("subnetLimit", 0, "The most connections made through the proxies of one subnet in any tick. Defaults to 0, no limit")
+	subnetV4Prefix *int = flag.Int("subnetV4Prefix", crawlrate.DefaultV4Prefix, "The prefix length of the subnet of an IPv4 proxy. Defaults to "+fmt.Sprint(crawlrate.DefaultV4Prefix))
+	subnetV6Prefix *int = flag.Int("subnetV6Prefix", crawlrate.DefaultV6Prefix, "The prefix length of the subnet of an IPv6 proxy. Defaults to "+fmt.Sprint(crawlrate.DefaultV6Prefix))
+	affinity   *bool = flag.Bool("affinity", false, "Keep each keyword on the same proxy between runs by consistent hashing. Defaults to false")
 )
 
-var (
-	badAlgorithm = errors.New("Unrecognised algorithm")
-)
+var weightings = map[string]crawlrate.Weighting{
+	"bottom": crawlrate.BottomHeavyWeighting,
+	"top":    crawlrate.TopHeavyWeighting,
+	"even":   crawlrate.EvenWeighting,
+}
+
+var lastRows = map[string]crawlrate.LastRowStrategy{
+	"cluster": crawlrate.ClusterLastRow,
+	"spread":  crawlrate.SpreadLastRow,
+}
 
 func main() {
+	flag.Usage = usage
 	flag.Parse()
 
 	if *keywordFile == "" {
@@ -47,56 +82,267 @@ func main() {
 		os.Exit(-1)
 	}
 
-	if *proxyFile == "" {
-		fmt.Println("missing option '-proxies': proxy filename must be specified")
-		os.Exit(-1)
-	}
-
 	if *algo == "" {
-		fmt.Println("missing option 'algorithm': algorithm must be either 'duration' or 'connections'")
+		fmt.Printf("missing option 'algorithm': algorithm must be one of '%s'\n", strings.Join(crawlrate.Algorithms(), "', '"))
 		os.Exit(-1)
 	}
 
-	keywords, err := readLines(*keywordFile)
+	algorithm, err := crawlrate.Lookup(*algo)
 	if err != nil {
 		log.Fatal(err)
 	}
-	sort.Sort(sort.StringSlice(keywords))	
 
-	proxies, err := readLines(*proxyFile)
+	lines, err := readLines(*keywordFile)
 	if err != nil {
 		log.Fatal(err)
 	}
+	sort.Sort(sort.StringSlice(lines))	
 
-	var p *crawlrate.Pulse
-	switch *algo {
-	case "duration":
-		p, _ = crawlrate.FixedDuration(len(keywords), len(proxies), int(avgJobRuntime.Seconds()), int(minimumDelay.Seconds()), int(timePeriod.Seconds()))
-		
-	case "connections":
-		p, _ = crawlrate.FixedConnections(len(keywords), len(proxies), int(avgJobRuntime.Seconds()), int(minimumDelay.Seconds()), *maximumConnections)
-		
-	default:
-		log.Fatal(badAlgorithm)
+	// Each line holds a keyword, optionally followed by tab separated fields for
+	// its priority and recrawl interval.
+	keywords := make([]crawlrate.Keyword, len(lines))
+	for i, line := range lines {
+		if keywords[i], err = crawlrate.ParseKeyword(line); err != nil {
+			log.Fatalf("%s line %q: %s", *keywordFile, line, err)
+		}
+	}
+
+	// Fan the keywords out across the targets up front, so the pulse has the
+	// capacity for every job.
+	if *targets != "" {
+		var ts []crawlrate.Target
+		for _, s := range strings.Split(*targets, ",") {
+			t, err := crawlrate.ParseTarget(strings.TrimSpace(s))
+			if err != nil {
+				log.Fatal(err)
+			}
+			ts = append(ts, t)
+		}
+		keywords = crawlrate.ExpandTargets(keywords, ts)
+	}
+
+	var proxies []crawlrate.Proxy
+	var groups []crawlrate.ProxyGroup
+	if *proxyFile != "" {
+		lines, err := readLines(*proxyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// Each line holds a proxy address, host:port, URL or range of
+		// addresses, optionally preceded by its region and a tab, and
+		// followed by a tab and its weight.
+		limit := crawlrate.RangeLimit{Max: *rangeLimit, Sample: *rangeSample, Seed: *seed}
+		if groups, err = crawlrate.ParseProxyRanges(lines, limit); err != nil {
+			log.Fatalf("%s: %s", *proxyFile, err)
+		}
+		for _, g := range groups {
+			proxies = append(proxies, g.Proxies...)
+		}
+	}
+
+	cooldown := crawlrate.Cooldown{Requests: *cooldownRequests, Rest: *cooldownRest}
+	subnets := crawlrate.SubnetLimit{V4Prefix: *subnetV4Prefix, V6Prefix: *subnetV6Prefix, Max: *subnetLimit}
+
+	in := crawlrate.PulseInputs{
+		KeywordCount:    len(keywords),
+		ProxyCount:      len(proxies),
+		AvgJobRuntime:   *avgJobRuntime,
+		MinimumDelay:    *minimumDelay,
+		TimePeriod:      *timePeriod,
+		ConnectionCount: *maximumConnections,
+		Throughput:      *throughput,
+		Cooldown:        cooldown,
+		Jitter:          *jitter,
+		SubnetLimit:     subnets,
+		Proxies:         proxies,
+	}
+
+	// Weighted proxies take a share of the work in proportion to their weight,
+	// and those without a weight in the file are of weight 1.
+	for _, p := range proxies {
+		if p.Weight != 0 {
+			in.Weights = make([]float64, len(proxies))
+			for i, p := range proxies {
+				in.Weights[i] = p.Weight
+				if p.Weight == 0 {
+					in.Weights[i] = 1
+				}
+			}
+			break
+		}
+	}
+
+	w, ok := weightings[*weighting]
+	if !ok {
+		log.Fatalf("unrecognised weighting %q", *weighting)
+	}
+
+	lr, ok := lastRows[*lastRow]
+	if !ok {
+		log.Fatalf("unrecognised last row strategy %q", *lastRow)
+	}
+
+	opts := []crawlrate.Option{crawlrate.WithWeighting(w), crawlrate.WithLastRow(lr), crawlrate.WithJitter(*jitter, *seed), crawlrate.WithCooldown(cooldown), crawlrate.WithSubnetLimit(subnets)}
+	if *affinity {
+		opts = append(opts, crawlrate.WithAffinity())
+	}
+
+	// Keywords and proxies tagged with regions are planned a group at a time,
+	// each group with its own pulse.
+	if isRegional(keywords, groups) {
+		if *horizon > 0 || *explain {
+			log.Fatal("regional proxies cannot be combined with -horizon or -explain")
+		}
+
+		cp, pulses, planErr := crawlrate.NewRegional(keywords, groups, algorithm, in, opts...)
+		runtimes := make(map[crawlrate.Proxy]time.Duration)
+		for g, p := range pulses {
+			if p == nil {
+				continue
+			}
+			if *debug {
+				fmt.Printf("Region %q pulse: %+v\n", groups[g].Region, p)
+			}
+			for _, proxy := range groups[g].Proxies {
+				runtimes[proxy] = p.Frequency
+			}
+		}
+		printPlan(cp, runtimes, planErr)
+		return
+	}
+
+	p, err := algorithm.Pulse(in)
+	if err != nil {
+		// Algorithms that calculate the number of proxies required do not
+		// need a proxy file, so only complain about it when it is needed.
+		if errors.Is(err, crawlrate.ErrNoProxies) && *proxyFile == "" {
+			fmt.Println("missing option '-proxies': proxy filename must be specified")
+			os.Exit(-1)
+		}
+		log.Fatalf("%s algorithm: %s", *algo, err)
 	}
 
 	if *debug {
 		fmt.Printf("Keywords: %d, %+v\n", len(keywords), keywords)
-		fmt.Printf("Proxies: %d, %+v\n", len(proxies), proxies)
+		fmt.Printf("Proxies: %d, %s\n", len(proxies), proxies)
 		fmt.Printf("Pulse: %+v\n", p)
 		fmt.Printf("Number of connections required for each proxy: %d\n", p.Volume)
-		fmt.Printf("Maximum runtime per keyword: %ds (%s)\n", int(p.Frequency.Seconds()), p.Frequency.String())
-		fmt.Printf("Total duration required to process all keywords: %ds (%s)\n", int(p.Duration.Seconds()), p.Duration.String())
+		fmt.Printf("Maximum runtime per keyword: %.3fs (%s)\n", p.Frequency.Seconds(), p.Frequency.String())
+		fmt.Printf("Total duration required to process all keywords: %.3fs (%s)\n", p.Duration.Seconds(), p.Duration.String())
+	}
+
+	if *explain {
+		printExplanation(p, p.Explain(in))
+		return
+	}
+
+	if p.Proxies > 0 {
+		fmt.Printf("Number of proxies required: %d\n", p.Proxies)
+
+		// Without a proxy file there is nothing to plan.
+		if *proxyFile == "" {
+			fmt.Printf("Number of connections required for each proxy: %d\n", p.Volume)
+			fmt.Printf("Maximum runtime per keyword: %.3fs (%s)\n", p.Frequency.Seconds(), p.Frequency.String())
+			fmt.Printf("Total duration required to process all keywords: %.3fs (%s)\n", p.Duration.Seconds(), p.Duration.String())
+			return
+		}
+
+		if len(proxies) < p.Proxies {
+			log.Fatalf("%d proxies required, only %d provided", p.Proxies, len(proxies))
+		}
+	}
+
+	var cp crawlrate.CrawlPlan
+	var planErr error
+	if *horizon > 0 {
+		cp, planErr = crawlrate.NewRecurring(keywords, proxies, p, *horizon, opts...)
+	} else {
+		cp, planErr = crawlrate.New(keywords, proxies, p, opts...)
+	}
+
+	runtimes := make(map[crawlrate.Proxy]time.Duration)
+	for _, proxy := range proxies {
+		runtimes[proxy] = p.Frequency
+	}
+	printPlan(cp, runtimes, planErr)
+}
+
+// isRegional reports whether any of the keywords or proxies are tagged with a region.
+func isRegional(keywords []crawlrate.Keyword, groups []crawlrate.ProxyGroup) bool {
+	for _, g := range groups {
+		if g.Region != "" {
+			return true
+		}
+	}
+	for _, k := range keywords {
+		if k.Region != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// printPlan prints the crawl plan along with the runtime of each proxy's jobs, then
+// fails if the plan could not be created or left keywords unscheduled.
+func printPlan(cp crawlrate.CrawlPlan, runtimes map[crawlrate.Proxy]time.Duration, planErr error) {
+	var unscheduled *crawlrate.UnscheduledError
+	if planErr != nil && !errors.As(planErr, &unscheduled) {
+		log.Fatal(planErr)
 	}
 
-	cp := crawlrate.New(keywords, proxies, p)
-	
-	w := new(tabwriter.Writer)
-	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
-    fmt.Fprintf(w, "Timestamp\tProxy\tConnection\tKeyword\tRuntime\n")
+	tw := new(tabwriter.Writer)
+	tw.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintf(tw, "Timestamp\tProxy\tConnection\tKeyword\tTarget\tRuntime\n")
 	for _, r := range cp {
-		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%d\n", int(r.Time.Seconds()), r.Proxy.String(), r.Conn, r.Keyword, int(p.Frequency.Seconds()))
-	} 
+		fmt.Fprintf(tw, "%.3f\t%s\t%d\t%s\t%s\t%.3f\n", r.Time.Seconds(), r.Proxy.String(), r.Conn, r.Keyword, r.Target, runtimes[r.Proxy].Seconds())
+	}
+	tw.Flush()
+
+	// Print whatever could be planned, but never let keywords silently drop
+	// out of the crawl.
+	if unscheduled != nil {
+		texts := make([]string, len(unscheduled.Keywords))
+		for i, k := range unscheduled.Keywords {
+			texts[i] = k.Text
+			if k.Target != (crawlrate.Target{}) {
+				texts[i] += " (" + k.Target.String() + ")"
+			}
+		}
+		log.Fatalf("%s: %s", unscheduled, strings.Join(texts, ", "))
+	}
+}
+
+func printExplanation(p *crawlrate.Pulse, e *crawlrate.Explanation) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Connections per proxy (volume):\t%d\n", p.Volume)
+	fmt.Fprintf(w, "Tick frequency:\t%s\n", p.Frequency)
+	fmt.Fprintf(w, "Duration:\t%s\n", p.Duration)
+	fmt.Fprintf(w, "Proxies:\t%d\n", e.Proxies)
+	fmt.Fprintf(w, "Keywords per proxy:\t%d\n", e.KeywordsPerProxy)
+	fmt.Fprintf(w, "Keywords per channel:\t%d\n", e.KeywordsPerChannel)
+	fmt.Fprintf(w, "Delay increment:\t%s\n", e.DelayIncrement)
+	fmt.Fprintf(w, "Connections per tick:\t%d\n", e.RowCapacity)
+	fmt.Fprintf(w, "Connections in total:\t%d\n", e.Capacity)
+	fmt.Fprintf(w, "Unused connections (last row):\t%d\n", e.UnusedCapacity)
+	fmt.Fprintf(w, "Unused connections (whole pulse):\t%d\n", e.UnusedPulseCapacity)
+	fmt.Fprintf(w, "Proxy utilisation:\t%.1f%%\n", e.Utilisation)
+	if p.TimePeriod > 0 {
+		fmt.Fprintf(w, "Time slack:\t%s\n", e.Slack)
+	}
+	w.Flush()
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+	flag.PrintDefaults()
+
+	fmt.Fprintf(os.Stderr, "\nAlgorithms:\n")
+	w := tabwriter.NewWriter(os.Stderr, 0, 8, 2, ' ', 0)
+	for _, name := range crawlrate.Algorithms() {
+		algorithm, _ := crawlrate.Lookup(name)
+		fmt.Fprintf(w, "  %s\t%s\n", name, algorithm.Description())
+	}
 	w.Flush()
 }
 
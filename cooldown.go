@@ -0,0 +1,88 @@
+package crawlrate
+
+import (
+	"time"
+)
+
+// Cooldown rests each proxy once it has served a number of keywords, so that proxies
+// are not flagged for being used continuously.
+type Cooldown struct {
+	Requests int           // Keywords a proxy serves before it must rest, or 0 for no cooldown
+	Rest     time.Duration // How long the proxy rests for once its last job has finished
+}
+
+// WithCooldown rests each proxy in the crawl plan for c.Rest once it has served
+// c.Requests keywords. A proxy is given no keywords in the ticks that start while it
+// rests, and the work moves to the other proxies or later ticks. A rest starts once
+// the last job before it has finished, allowing for the jitter of the plan, so a
+// jittered job never cuts it short. Pass the same cooldown and jitter to the pulse
+// algorithm in PulseInputs so the pulse allows for the rests.
+func WithCooldown(c Cooldown) Option {
+	return func(o *options) {
+		o.cooldown = c
+	}
+}
+
+func (c Cooldown) validate() error {
+	if c.Requests < 0 || c.Rest < 0 {
+		return &InputError{"cooldown", c, ErrInvalidCooldown}
+	}
+	return nil
+}
+
+// resting tracks the rests of a cooldown for each proxy, a tick at a time.
+type resting struct {
+	Cooldown
+	served []int           // Keywords each proxy has served since it last rested
+	until  []time.Duration // Time each proxy rests until
+}
+
+func (c Cooldown) resting(proxyCount int) *resting {
+	return &resting{
+		Cooldown: c,
+		served:   make([]int, proxyCount),
+		until:    make([]time.Duration, proxyCount),
+	}
+}
+
+// available returns the number of connections available to each proxy in the tick
+// starting at t, given the number of connections each proxy is allowed. A resting
+// proxy has none, and no proxy has more than it may serve before its next rest.
+func (r *resting) available(volumes []int, t time.Duration) []int {
+	if r.Requests <= 0 {
+		return volumes
+	}
+
+	row := make([]int, len(volumes))
+	for proxy, volume := range volumes {
+		if t < r.until[proxy] {
+			continue
+		}
+
+		n := volume
+		if left := r.Requests - r.served[proxy]; left < n {
+			n = left
+		}
+		row[proxy] = n
+	}
+	return row
+}
+
+// serve records the number of connections each proxy served in a tick whose jobs
+// have all finished by end, resting the proxies that have served c.Requests.
+func (r *resting) serve(row []int, end time.Duration) {
+	if r.Requests <= 0 {
+		return
+	}
+
+	for proxy, n := range row {
+		if n == 0 {
+			continue
+		}
+		r.served[proxy] += n
+		if r.served[proxy] == r.Requests {
+			r.served[proxy] = 0
+			r.until[proxy] = end + r.Rest
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package crawlrate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_CooldownRowVolumes(t *testing.T) {
+	c := Cooldown{Requests: 3, Rest: minute}
+	grid := rowLimits{cooldown: c}.rowVolumes([]int{2, 1}, 6, minute)
+
+	// The first proxy serves 2 then 1 before resting a tick, the second serves
+	// 1 a tick and rests a tick after its third
+	out := [][]int{{2, 1}, {1, 1}, {0, 1}, {2, 0}, {1, 1}, {0, 1}}
+	for row := range out {
+		for proxy := range out[row] {
+			if grid[row][proxy] != out[row][proxy] {
+				t.Errorf("Error test: row %d exp: %v got: %v\n", row, out[row], grid[row])
+				break
+			}
+		}
+	}
+}
+
+func Test_CooldownJitter(t *testing.T) {
+	// Jobs that start up to 30s into their tick push each rest back a tick
+	grid := rowLimits{cooldown: Cooldown{Requests: 2, Rest: minute}, jitter: 30 * time.Second}.rowVolumes([]int{2}, 6, minute)
+	out := [][]int{{2}, {0}, {0}, {2}, {0}, {0}}
+	if fmt.Sprint(grid) != fmt.Sprint(out) {
+		t.Errorf("Expected %v. Got: %v\n", out, grid)
+	}
+
+	// The rest follows the end of the latest job before it, however much later
+	// than the other it started
+	p := &Pulse{Volume: 2, Frequency: minute, Duration: 6 * minute}
+	cp, err := New(generateKeywords("keyword-", 4), generateProxies("127.0.0.", 1), p, WithCooldown(Cooldown{Requests: 2, Rest: minute}), WithJitter(30*time.Second, 42), WithOrdering(ByStart))
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+	if len(cp) != 4 {
+		t.Fatalf("Expected 4 rules. Got: %d\n", len(cp))
+	}
+	if rest := cp[2].Time - (cp[1].Time + p.Frequency); rest < minute {
+		t.Errorf("Expected a rest of at least %s. Got: %s %v\n", minute, rest, cp)
+	}
+}
+
+func Test_Cooldown(t *testing.T) {
+	// Proxies rest for a tick after every 2 keywords
+	p := &Pulse{Volume: 1, Frequency: minute, Duration: 5 * minute}
+	cp, err := New(generateKeywords("keyword-", 7), generateProxies("127.0.0.", 2), p, WithCooldown(Cooldown{Requests: 2, Rest: minute}))
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+
+	comparePlan(t, 0, cp, CrawlPlan{
+		{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+		{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-1", Target{}},
+		{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
+		{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-3", Target{}},
+		{time.Duration(180) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-4", Target{}},
+		{time.Duration(180) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-5", Target{}},
+		{time.Duration(240) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-6", Target{}},
+	})
+
+	// Work shifts to the proxy that is not resting
+	p = &Pulse{Volume: 1, Frequency: minute, Duration: 3 * minute, Volumes: []int{2, 1}}
+	cp, _ = New(generateKeywords("keyword-", 4), generateProxies("127.0.0.", 2), p, WithCooldown(Cooldown{Requests: 2, Rest: minute}))
+	comparePlan(t, 1, cp, CrawlPlan{
+		{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+		{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+		{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+		{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-3", Target{}},
+	})
+
+	if _, err := New(generateKeywords("keyword-", 1), generateProxies("127.0.0.", 1), p, WithCooldown(Cooldown{Requests: -1})); !errors.Is(err, ErrInvalidCooldown) {
+		t.Errorf("Expected %v. Got: %v\n", ErrInvalidCooldown, err)
+	}
+}
+
+var cooldownPulseTests = []struct {
+	algorithm string
+	in        PulseInputs
+	out       *Pulse
+	err       error
+}{
+	// 4 ticks of work take 5 with a rest after every 2
+	{"connections", PulseInputs{KeywordCount: 8, ProxyCount: 2, AvgJobRuntime: minute, ConnectionCount: 1, Cooldown: Cooldown{Requests: 2, Rest: minute}},
+		&Pulse{Volume: 1, Frequency: minute, Duration: 5 * minute}, nil},
+	{"duration", PulseInputs{KeywordCount: 8, ProxyCount: 2, AvgJobRuntime: minute, TimePeriod: 5 * minute, Cooldown: Cooldown{Requests: 2, Rest: minute}},
+		&Pulse{Volume: 1, Frequency: minute, Duration: 5 * minute}, nil},
+
+	// A shorter period needs more connections to make up for the rest
+	{"duration", PulseInputs{KeywordCount: 8, ProxyCount: 2, AvgJobRuntime: minute, TimePeriod: 4 * minute, Cooldown: Cooldown{Requests: 2, Rest: minute}},
+		&Pulse{Volume: 2, Frequency: minute, Duration: 4 * minute}, nil},
+	{"duration", PulseInputs{KeywordCount: 8, ProxyCount: 2, AvgJobRuntime: minute, TimePeriod: 4 * minute, Cooldown: Cooldown{Requests: 1, Rest: time.Hour}},
+		nil, ErrInfeasibleWindow},
+
+	// Jitter delays each rest, so the proxies take a tick longer to serve their 4
+	{"connections", PulseInputs{KeywordCount: 8, ProxyCount: 2, AvgJobRuntime: minute, ConnectionCount: 1, Cooldown: Cooldown{Requests: 2, Rest: minute}, Jitter: 30 * time.Second},
+		&Pulse{Volume: 1, Frequency: minute, Duration: 6 * minute}, nil},
+
+	// Or more proxies
+	{"proxies", PulseInputs{KeywordCount: 8, AvgJobRuntime: minute, ConnectionCount: 1, TimePeriod: 4 * minute, Cooldown: Cooldown{Requests: 2, Rest: minute}},
+		&Pulse{Volume: 1, Frequency: minute, Duration: 4 * minute, Proxies: 3}, nil},
+
+	{"connections", PulseInputs{KeywordCount: 8, ProxyCount: 2, AvgJobRuntime: minute, ConnectionCount: 1, Cooldown: Cooldown{Rest: -minute}},
+		nil, ErrInvalidCooldown},
+}
+
+func Test_CooldownPulse(t *testing.T) {
+	for k, tt := range cooldownPulseTests {
+		algorithm, _ := Lookup(tt.algorithm)
+		p, err := algorithm.Pulse(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Error test: %d err exp: %v got: %v\n", k, tt.err, err)
+			continue
+		}
+		if tt.out != nil {
+			comparePulse(t, k, p, tt.out)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package crawlrate
 
 import (
-	"net"
+	"sort"
 	"time"
 )
 
@@ -49,7 +49,7 @@ A crawl plan is created from a Pulse. The following calculations are made:
 
     numberOfRows = pulse.Duration / pulse.Frequency
 
-    numberOfColumns = proxies []string  cellValue = pulse.Volume
+    numberOfColumns = proxies []Proxy  cellValue = pulse.Volume (or pulse.Volumes[column])
 
     keywordCount (to calculate last row)
 
@@ -64,36 +64,62 @@ which means:
 
 type CrawlPlan []CrawlRule
 
-func New(keywords, proxies []string, pulse *Pulse) (cr CrawlPlan) {
-	/*
-		NOTE:
-		May have to add a 'last row' check so that I can apply different distribution
-		algorithms e.g distribute remaining keywords across all proxies vs. cluster as
-		many connections to as few proxies as possible. Bear in mind that some tests only
-		have a single row, so there is no "last row" to balance. What do we do in those
-		circumstances?
-	*/
-	var currentKeyword int = 0
-
-outerLoop:
-	for t := 0; t < int(pulse.Duration.Seconds()); t = t + int(pulse.Frequency.Seconds()) {
-		for _, proxy := range proxies {
-			for conn := 0; conn < pulse.Volume; conn++ {
-				cr = append(cr, CrawlRule{time.Duration(t) * time.Second, net.ParseIP(proxy), conn, keywords[currentKeyword]})
-				currentKeyword++
-				if currentKeyword >= len(keywords) {
-					break outerLoop
-				}
-			}
+// New creates a crawl plan that schedules the keywords across the proxies according to
+// the pulse, shaped by any options given. Keywords are scheduled in order of priority,
+// highest first. If the pulse does not have the capacity to schedule every keyword,
+// the plan is returned along with an *UnscheduledError listing the keywords left out,
+// which are those of the lowest priority.
+func New(keywords []Keyword, proxies []Proxy, pulse *Pulse, opts ...Option) (cr CrawlPlan, err error) {
+	if pulse.Frequency <= 0 {
+		return nil, &InputError{"pulse.Frequency", pulse.Frequency, ErrInvalidPulse}
+	}
+
+	o := newOptions(opts)
+	if err := o.cooldown.validate(); err != nil {
+		return nil, err
+	}
+	if err := o.subnets.validate(); err != nil {
+		return nil, err
+	}
+	ws := weights(proxies)
+	if err := validateWeights(ws); err != nil {
+		return nil, err
+	}
+	keywords = byPriority(ExpandTargets(keywords, o.targets))
+
+	// Work out how many keywords each row of the plan will hold. The number
+	// of rows is the number of ticks that start within the pulse duration,
+	// and the proxies resting in a tick, or held back by the limit of their
+	// subnet, leave it with fewer connections.
+	rows := int((pulse.Duration + pulse.Frequency - 1) / pulse.Frequency)
+	volumes, _ := pulse.volumes(ws)
+	grid := o.rowLimits(proxies).rowVolumes(volumes, rows, pulse.Frequency)
+	capacities := make([]int, rows)
+	for row := range grid {
+		for _, v := range grid[row] {
+			capacities[row] += v
 		}
 	}
+	counts := o.weighting.rowCounts(len(keywords), capacities)
+	jitter := offsets(o.jitter, o.seed, pulse.Frequency, volumes)
+
+	placements, unscheduled := o.assigner(proxies)(keywords, counts, grid, o.lastRow)
+
+	for _, p := range placements {
+		t := time.Duration(p.row)*pulse.Frequency + jitter[p.proxy][p.conn]
+		cr = append(cr, CrawlRule{t, proxies[p.proxy], p.conn, keywords[p.keyword].Text, keywords[p.keyword].Target})
+	}
 
-	orderedBy(start, proxy, increasingConnections).Sort(cr)
-	return
+	OrderedBy(o.ordering...).Sort(cr)
+
+	if len(unscheduled) > 0 {
+		return cr, &UnscheduledError{Keywords: unscheduled}
+	}
+	return cr, nil
 }
 
 // Filter
-func (cp CrawlPlan) Filter(t int64, f func(int64, CrawlRule) bool) CrawlPlan {
+func (cp CrawlPlan) Filter(t time.Duration, f func(time.Duration, CrawlRule) bool) CrawlPlan {
     vsf := make(CrawlPlan, 0)
     for _, v := range cp {
         if f(t, v) {
@@ -111,44 +137,69 @@ func (cp CrawlPlan) Map(f func(CrawlRule) CrawlRule) {
 }
 
 
-func (cp CrawlPlan) Distinct() []int64 {
-    vsm := make(map[int64]bool)
-    for _, v := range cp {
-        t := int64(v.Time/time.Second)
-        vsm[t] = true
-    }
+// Distinct returns the distinct rule start times within the plan, in ascending order.
+func (cp CrawlPlan) Distinct() []time.Duration {
+	vsm := make(map[time.Duration]bool)
+	for _, v := range cp {
+		vsm[v.Time] = true
+	}
 
-    // now flip the distinct keys  
-    d := make([]int64, len(vsm))
-    pos := 0
-    for k, _ := range vsm {
-        d[pos] = k
-        pos++
-    }
-    return d
+	// now flip the distinct keys
+	d := make([]time.Duration, 0, len(vsm))
+	for k := range vsm {
+		d = append(d, k)
+	}
+	sort.Slice(d, func(i, j int) bool {
+		return d[i] < d[j]
+	})
+	return d
 }
 
 
-func start(c1, c2 *CrawlRule) bool {
-	return c1.Time.Seconds() < c2.Time.Seconds()
+// ByStart orders crawl rules by the time they start.
+func ByStart(c1, c2 *CrawlRule) bool {
+	return c1.Time < c2.Time
 }
 
-func proxy(c1, c2 *CrawlRule) bool {
-	return c1.Proxy.String() < c2.Proxy.String()
+// ByProxy orders crawl rules by their proxy, stacking them to the left. Proxies are
+// ordered by address, so the proxies of a subnet sort together, ahead of those given
+// by hostname.
+func ByProxy(c1, c2 *CrawlRule) bool {
+	p1, p2 := &c1.Proxy, &c2.Proxy
+	switch {
+	case p1.Host != p2.Host:
+		return lessHost(p1, p2)
+	case p1.Port != p2.Port:
+		return p1.Port < p2.Port
+	case p1.Scheme != p2.Scheme:
+		return p1.Scheme < p2.Scheme
+	}
+	return p1.Username < p2.Username
 }
 
-func increasingConnections(c1, c2 *CrawlRule) bool {
+// ByIncreasingConnections orders crawl rules by their connection, lowest first.
+func ByIncreasingConnections(c1, c2 *CrawlRule) bool {
 	return c1.Conn < c2.Conn
 }
 
-func decreasingConnections(c1, c2 *CrawlRule) bool {
+// ByDecreasingConnections orders crawl rules by their connection, highest first.
+func ByDecreasingConnections(c1, c2 *CrawlRule) bool {
 	return c1.Conn > c2.Conn // Note: > orders downwards.
 }
 
+// BottomHeavy sorts the rules of a crawl plan into the order they start.
+//
+// Deprecated: sorting does not change the shape of a plan. Create the plan with
+// WithWeighting(BottomHeavyWeighting), which New uses by default.
 func BottomHeavy(cp CrawlPlan) {
-	orderedBy(start, proxy, increasingConnections).Sort(cp)
+	OrderedBy(ByStart, ByProxy, ByIncreasingConnections).Sort(cp)
 }
 
+// TopHeavy sorts the rules of a crawl plan into the order they start, exactly as
+// BottomHeavy does.
+//
+// Deprecated: sorting does not change the shape of a plan. Create the plan with
+// WithWeighting(TopHeavyWeighting) to put the partial row at the start.
 func TopHeavy(cp CrawlPlan) {
-	orderedBy(start, proxy, increasingConnections).Sort(cp)
+	BottomHeavy(cp)
 }
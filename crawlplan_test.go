@@ -1,7 +1,7 @@
 package crawlrate
 
 import (
-	"net"
+	"errors"
 	"testing"
 	"time"
 	"fmt"
@@ -13,114 +13,422 @@ var bottomTests = []struct {
 	out                      CrawlPlan
 }{
 	{
-		1, 1, &Pulse{1, time.Duration(60) * time.Second, time.Duration(60) * time.Second},
+		1, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second},
 		CrawlPlan{
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
 		},
 	},
 	{
-		2, 1, &Pulse{1, time.Duration(60) * time.Second, time.Duration(120) * time.Second},
+		2, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second},
 		CrawlPlan{
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-1"},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
 		},
 	},
 	{
-		2, 1, &Pulse{2, time.Duration(60) * time.Second, time.Duration(60) * time.Second},
+		2, 1, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second},
 		CrawlPlan{
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 1, "keyword-1"},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
 		},
 	},
 	{
-		3, 1, &Pulse{1, time.Duration(60) * time.Second, time.Duration(180) * time.Second},
+		3, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
 		CrawlPlan{
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-1"},
-			{time.Duration(120) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-2"},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
 		},
 	},
 	{
-		3, 1, &Pulse{2, time.Duration(60) * time.Second, time.Duration(120) * time.Second},
+		3, 1, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second},
 		CrawlPlan{
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 1, "keyword-1"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-2"},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
 		},
 	},
 	{
-		3, 1, &Pulse{3, time.Duration(60) * time.Second, time.Duration(60) * time.Second},
+		3, 1, &Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second},
 		CrawlPlan{
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 1, "keyword-1"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 2, "keyword-2"},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 2, "keyword-2", Target{}},
 		},
 	},
 	{
-		15, 3, &Pulse{2, time.Duration(60) * time.Second, time.Duration(180) * time.Second},
+		15, 3, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
 		CrawlPlan{
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 1, "keyword-1"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.1"), 0, "keyword-2"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.1"), 1, "keyword-3"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.2"), 0, "keyword-4"},
-			{time.Duration(0) * time.Second, net.ParseIP("127.0.0.2"), 1, "keyword-5"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-6"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.0"), 1, "keyword-7"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.1"), 0, "keyword-8"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.1"), 1, "keyword-9"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.2"), 0, "keyword-10"},
-			{time.Duration(60) * time.Second, net.ParseIP("127.0.0.2"), 1, "keyword-11"},
-			{time.Duration(120) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-12"},
-			{time.Duration(120) * time.Second, net.ParseIP("127.0.0.0"), 1, "keyword-13"},
-			{time.Duration(120) * time.Second, net.ParseIP("127.0.0.1"), 0, "keyword-14"},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-3", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-4", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.2"}, 1, "keyword-5", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-6", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-7", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-8", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-9", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-10", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 1, "keyword-11", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-12", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-13", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-14", Target{}},
+		},
+	},
+	{
+		5, 2, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Volumes: []int{2, 1}},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-3", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-4", Target{}},
+		},
+	},
+	{
+		3, 1, &Pulse{Volume: 1, Frequency: time.Duration(333) * time.Millisecond, Duration: time.Duration(1) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Millisecond, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(333) * time.Millisecond, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+			{time.Duration(666) * time.Millisecond, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
 		},
 	},
 }
 
 func Test_BottomDistribution(t *testing.T) {
 	for k, tt := range bottomTests {
-		cp := New(generateLists("keyword-", tt.keywordCount), generateLists("127.0.0.", tt.proxyCount), tt.pulse)
+		cp, err := New(generateKeywords("keyword-", tt.keywordCount), generateProxies("127.0.0.", tt.proxyCount), tt.pulse)
+		if err != nil {
+			t.Errorf("Test %d: Error: %s\n", k, err)
+		}
+		comparePlan(t, k, cp, tt.out)
+	}
+}
 
-		if len(cp) != len(tt.out) {
-			t.Errorf("Test %d: Non-equal slice lengths. Got: %d Expected: %d\n", k, len(cp), len(tt.out))
+var topTests = []struct {
+	keywordCount, proxyCount int
+	pulse                    *Pulse
+	out                      CrawlPlan
+}{
+	{
+		1, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+		},
+	},
+	{
+		2, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+		},
+	},
+	{
+		2, 1, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+		},
+	},
+	{
+		3, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
+		},
+	},
+	{
+		3, 1, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-2", Target{}},
+		},
+	},
+	{
+		3, 1, &Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 2, "keyword-2", Target{}},
+		},
+	},
+	{
+		15, 3, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-3", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-4", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-5", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-6", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-7", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 1, "keyword-8", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-9", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-10", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-11", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-12", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-13", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.2"}, 1, "keyword-14", Target{}},
+		},
+	},
+	{
+		5, 2, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Volumes: []int{2, 1}},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-3", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-4", Target{}},
+		},
+	},
+
+	// More capacity than keywords: full rows fill the end of the window
+	{
+		2, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
+		CrawlPlan{
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+		},
+	},
+	{
+		3, 1, &Pulse{Volume: 1, Frequency: time.Duration(333) * time.Millisecond, Duration: time.Duration(1) * time.Second},
+		CrawlPlan{
+			{time.Duration(333) * time.Millisecond, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(666) * time.Millisecond, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+			{time.Duration(999) * time.Millisecond, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
+		},
+	},
+}
+
+func Test_TopDistribution(t *testing.T) {
+	for k, tt := range topTests {
+		cp, err := New(generateKeywords("keyword-", tt.keywordCount), generateProxies("127.0.0.", tt.proxyCount), tt.pulse, WithWeighting(TopHeavyWeighting))
+		if err != nil {
+			t.Errorf("Test %d: Error: %s\n", k, err)
 		}
-		
-		for n:=0; n<len(cp); n++ {
-			if cp[n].Time != tt.out[n].Time {
-				t.Errorf("Test %d: Time not equal. Got: %d Expected: %d\n", k, cp[n].Time, tt.out[n].Time)
-			}
-			
-			if cp[n].Proxy.String() != tt.out[n].Proxy.String() {
-				t.Errorf("Test %d: Proxy not equal. Got: %s Expected: %s\n", k, cp[n].Proxy.String(), tt.out[n].Proxy.String())
-			}
-			
-			if cp[n].Conn != tt.out[n].Conn {
-				t.Errorf("Test %d: Connection count not equal. Got: %d Expected: %d\n", k, cp[n].Conn, tt.out[n].Conn)
-			}
-			
-			if cp[n].Conn != tt.out[n].Conn {
-				t.Errorf("Test %d: Connection count not equal. Got: %d Expected: %d\n", k, cp[n].Conn, tt.out[n].Conn)
-			}
-			
-			if cp[n].Keyword != tt.out[n].Keyword {
-				t.Errorf("Test %d: Keyword not equal. Got: %d Expected: %d\n", k, cp[n].Keyword, tt.out[n].Keyword)
-			}			
+		comparePlan(t, k, cp, tt.out)
+	}
+}
+
+var spreadTests = []struct {
+	keywordCount, proxyCount int
+	pulse                    *Pulse
+	weighting                Weighting
+	out                      CrawlPlan
+}{
+	{
+		15, 3, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}, BottomHeavyWeighting,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-3", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-4", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.2"}, 1, "keyword-5", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-6", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-7", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-8", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-9", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-10", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 1, "keyword-11", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-12", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-13", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-14", Target{}},
+		},
+	},
+	{
+		15, 3, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}, TopHeavyWeighting,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-2", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-3", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-4", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-5", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-6", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-7", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 1, "keyword-8", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-9", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-10", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-11", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-12", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-13", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.2"}, 1, "keyword-14", Target{}},
+		},
+	},
+
+	// A single partial row is spread just the same
+	{
+		3, 3, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}, BottomHeavyWeighting,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-2", Target{}},
+		},
+	},
+	{
+		4, 2, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Volumes: []int{2, 1}}, BottomHeavyWeighting,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-3", Target{}},
+		},
+	},
+	{
+		5, 2, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Volumes: []int{2, 1}}, BottomHeavyWeighting,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-3", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-4", Target{}},
+		},
+	},
+}
+
+func Test_SpreadLastRow(t *testing.T) {
+	for k, tt := range spreadTests {
+		cp, err := New(generateKeywords("keyword-", tt.keywordCount), generateProxies("127.0.0.", tt.proxyCount), tt.pulse, WithWeighting(tt.weighting), WithLastRow(SpreadLastRow))
+		if err != nil {
+			t.Errorf("Test %d: Error: %s\n", k, err)
+		}
+		comparePlan(t, k, cp, tt.out)
+	}
+}
+
+var evenTests = []struct {
+	keywordCount, proxyCount int
+	pulse                    *Pulse
+	out                      CrawlPlan
+}{
+	{
+		14, 3, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-3", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-4", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-5", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-6", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-7", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-8", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.2"}, 0, "keyword-9", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-10", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-11", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-12", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-13", Target{}},
+		},
+	},
+	{
+		4, 1, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-3", Target{}},
+		},
+	},
+
+	// Full rows are unchanged
+	{
+		3, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+			{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+			{time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
+		},
+	},
+}
+
+func Test_EvenDistribution(t *testing.T) {
+	for k, tt := range evenTests {
+		cp, err := New(generateKeywords("keyword-", tt.keywordCount), generateProxies("127.0.0.", tt.proxyCount), tt.pulse, WithWeighting(EvenWeighting))
+		if err != nil {
+			t.Errorf("Test %d: Error: %s\n", k, err)
+		}
+		comparePlan(t, k, cp, tt.out)
+	}
+
+	// More keywords than capacity still fills every row
+	p := &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}
+	cp, err := New(generateKeywords("keyword-", 3), generateProxies("127.0.0.", 1), p, WithWeighting(EvenWeighting))
+	if len(cp) != 2 || !errors.Is(err, ErrUnscheduled) {
+		t.Errorf("Expected 2 rules and unscheduled keywords. Got: %d %v\n", len(cp), err)
+	}
+}
+
+func comparePlan(t *testing.T, k int, cp, out CrawlPlan) {
+	if len(cp) != len(out) {
+		t.Errorf("Test %d: Non-equal slice lengths. Got: %d Expected: %d\n", k, len(cp), len(out))
+		return
+	}
+
+	for n := 0; n < len(cp); n++ {
+		if cp[n].Time != out[n].Time {
+			t.Errorf("Test %d: Time not equal. Got: %s Expected: %s\n", k, cp[n].Time, out[n].Time)
+		}
+
+		if cp[n].Proxy.String() != out[n].Proxy.String() {
+			t.Errorf("Test %d: Proxy not equal. Got: %s Expected: %s\n", k, cp[n].Proxy.String(), out[n].Proxy.String())
+		}
+
+		if cp[n].Conn != out[n].Conn {
+			t.Errorf("Test %d: Connection count not equal. Got: %d Expected: %d\n", k, cp[n].Conn, out[n].Conn)
+		}
+
+		if cp[n].Keyword != out[n].Keyword {
+			t.Errorf("Test %d: Keyword not equal. Got: %s Expected: %s\n", k, cp[n].Keyword, out[n].Keyword)
+		}
+
+		if cp[n].Target != out[n].Target {
+			t.Errorf("Test %d: Target not equal. Got: %s Expected: %s\n", k, cp[n].Target, out[n].Target)
 		}
 	}
 }
 
+func Test_Unscheduled(t *testing.T) {
+	// 3 ticks of 2 proxies with 1 connection only has room for 6 of 8 keywords
+	p := &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}
+	cp, err := New(generateKeywords("keyword-", 8), generateProxies("127.0.0.", 2), p)
+
+	if len(cp) != 6 {
+		t.Errorf("Non-equal slice lengths. Got: %d Expected: %d\n", len(cp), 6)
+	}
+
+	var unscheduled *UnscheduledError
+	if !errors.As(err, &unscheduled) || !errors.Is(err, ErrUnscheduled) {
+		t.Fatalf("Expected UnscheduledError. Got: %v\n", err)
+	}
+	if len(unscheduled.Keywords) != 2 || unscheduled.Keywords[0].Text != "keyword-6" || unscheduled.Keywords[1].Text != "keyword-7" {
+		t.Errorf("Unscheduled keywords wrong. Got: %v Expected: %v\n", unscheduled.Keywords, []string{"keyword-6", "keyword-7"})
+	}
+}
+
+func Test_NoKeywords(t *testing.T) {
+	p := &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}
+	cp, err := New(nil, generateProxies("127.0.0.", 2), p)
+	if err != nil || len(cp) != 0 {
+		t.Errorf("Expected empty plan. Got: %v %v\n", cp, err)
+	}
+}
+
 func Test_Distinct(t *testing.T) {
     var tableCr = CrawlPlan{
-        {time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-        {time.Duration(60) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-1"},
-        {time.Duration(120) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-2"},
-        {time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-        {time.Duration(60) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-1"},
-        {time.Duration(120) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-2"},
-        {time.Duration(0) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-0"},
-        {time.Duration(60) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-1"},
-        {time.Duration(120) * time.Second, net.ParseIP("127.0.0.0"), 0, "keyword-2"},
+        {time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+        {time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+        {time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
+        {time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+        {time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+        {time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
+        {time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+        {time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", Target{}},
+        {time.Duration(120) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-2", Target{}},
     }
     
     dist := tableCr.Distinct()
@@ -130,16 +438,16 @@ func Test_Distinct(t *testing.T) {
         t.Errorf("Distinct count wrong. Got: %d Expected: %d\n", distinctCount, 3)
     }
 
-    if dist[0] != 0 {
-        t.Errorf("Distinct item 0 is wrong. Got: %d Expected: %d\n", dist[0], 0)
+    if dist[0] != time.Duration(0) * time.Second {
+        t.Errorf("Distinct item 0 is wrong. Got: %s Expected: %s\n", dist[0], time.Duration(0) * time.Second)
     }
 
-    if dist[1] != 60 {
-        t.Errorf("Distinct item 0 is wrong. Got: %d Expected: %d\n", dist[1], 60)
+    if dist[1] != time.Duration(60) * time.Second {
+        t.Errorf("Distinct item 0 is wrong. Got: %s Expected: %s\n", dist[1], time.Duration(60) * time.Second)
     }
 
-    if dist[2] != 120 {
-        t.Errorf("Distinct item 0 is wrong. Got: %d Expected: %d\n", dist[2], 120)
+    if dist[2] != time.Duration(120) * time.Second {
+        t.Errorf("Distinct item 0 is wrong. Got: %s Expected: %s\n", dist[2], time.Duration(120) * time.Second)
     }
 }
 
@@ -150,3 +458,19 @@ func generateLists(prefix string, count int) []string {
 	}
 	return out
 }
+
+func generateProxies(prefix string, count int) []Proxy {
+	var out []Proxy
+	for _, host := range generateLists(prefix, count) {
+		out = append(out, Proxy{Host: host})
+	}
+	return out
+}
+
+func generateKeywords(prefix string, count int) []Keyword {
+	var out []Keyword
+	for _, text := range generateLists(prefix, count) {
+		out = append(out, Keyword{Text: text})
+	}
+	return out
+}
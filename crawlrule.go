@@ -1,18 +1,18 @@
 package crawlrate
 
 import (
-    "net"
 	"time"
     "fmt"
 )
 
 type CrawlRule struct {
 	Time    time.Duration
-	Proxy   net.IP
+	Proxy   Proxy
 	Conn    int
 	Keyword string
+	Target  Target
 }
 
 func (cr CrawlRule) String() string {
-	return fmt.Sprintf("[%ds]\t%s\t%d\t%s\n", int(cr.Time.Seconds()), cr.Proxy.String(), cr.Conn, cr.Keyword)
+	return fmt.Sprintf("[%.3fs]\t%s\t%d\t%s\t%s\n", cr.Time.Seconds(), cr.Proxy.String(), cr.Conn, cr.Keyword, cr.Target)
 }
\ No newline at end of file
@@ -0,0 +1,145 @@
+package crawlrate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Errors returned by the pulse algorithms. Invalid inputs are reported as an
+// *InputError wrapping one of these, so they can be checked with errors.Is.
+var (
+	ErrNoKeywords        = errors.New("no keywords")
+	ErrNoProxies         = errors.New("no proxies")
+	ErrNoConnections     = errors.New("connection count must be greater than zero")
+	ErrInvalidRuntime    = errors.New("job runtime must be greater than zero")
+	ErrInvalidDelay      = errors.New("minimum delay must not be negative")
+	ErrInvalidCapacity   = errors.New("proxy capacity must not be negative")
+	ErrInvalidThroughput = errors.New("throughput must be greater than zero")
+	ErrInvalidConfidence = errors.New("confidence must be greater than 0 and no more than 1")
+	ErrInvalidCost       = errors.New("cost must not be negative")
+	ErrInfeasibleWindow  = errors.New("total keyword time exceeds duration")
+	ErrCapacityExceeded  = errors.New("proxy capacity exceeded")
+	ErrMissedDeadline    = errors.New("no pulse finishes within the deadline")
+	ErrUnknownAlgorithm  = errors.New("unrecognised algorithm")
+	ErrInvalidPulse      = errors.New("pulse frequency must be greater than zero")
+	ErrUnscheduled       = errors.New("keywords could not be scheduled")
+	ErrInvalidPriority   = errors.New("priority must be a whole number")
+	ErrInvalidInterval   = errors.New("recrawl interval must not be negative")
+	ErrInvalidHorizon    = errors.New("horizon must be greater than zero")
+	ErrInvalidTarget     = errors.New("target must be engine/locale/device")
+	ErrInvalidProxy      = errors.New("proxy must be a host, host:port or URL")
+	ErrInvalidCooldown   = errors.New("cooldown requests and rest must not be negative")
+	ErrDuplicateRegion   = errors.New("region has more than one proxy group")
+	ErrInvalidWeight     = errors.New("proxy weight must be greater than zero")
+	ErrRangeTooLarge     = errors.New("proxy range holds more addresses than the limit")
+	ErrInvalidSubnet     = errors.New("subnet limit must not be negative, with prefixes within the address length")
+	ErrSubnetProxies     = errors.New("subnet limit needs the address of every proxy")
+	ErrSharedSubnet      = errors.New("subnet holds proxies of more than one region")
+)
+
+// InputError records an invalid input to a pulse algorithm and the reason it is
+// invalid.
+type InputError struct {
+	Input string      // Name of the invalid input
+	Value interface{} // Value supplied for the input
+	Err   error       // Reason the value is invalid
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("invalid %s %v: %s", e.Input, e.Value, e.Err)
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// UnscheduledError is returned along with a crawl plan when the pulse does not have
+// the capacity to schedule every keyword. It lists the keywords left out of the plan,
+// so that keywords are never silently dropped.
+type UnscheduledError struct {
+	Keywords []Keyword // Keywords missing from the plan, once for each crawl missed
+}
+
+func (e *UnscheduledError) Error() string {
+	return fmt.Sprintf("%d %s", len(e.Keywords), ErrUnscheduled)
+}
+
+func (e *UnscheduledError) Unwrap() error {
+	return ErrUnscheduled
+}
+
+// RegionError records the region of the proxy group a plan could not be created for.
+type RegionError struct {
+	Region string
+	Err    error
+}
+
+func (e *RegionError) Error() string {
+	return fmt.Sprintf("region %q: %s", e.Region, e.Err)
+}
+
+func (e *RegionError) Unwrap() error {
+	return e.Err
+}
+
+// validateJobs checks the inputs common to every pulse algorithm.
+func validateJobs(keywordCount int, avgJobRuntime, minimumDelay time.Duration) error {
+	switch {
+	case keywordCount <= 0:
+		return &InputError{"keywordCount", keywordCount, ErrNoKeywords}
+	case avgJobRuntime <= 0:
+		return &InputError{"avgJobRuntime", avgJobRuntime, ErrInvalidRuntime}
+	case minimumDelay < 0:
+		return &InputError{"minimumDelay", minimumDelay, ErrInvalidDelay}
+	}
+	return nil
+}
+
+// validateProxies checks the number of proxies available.
+func validateProxies(proxyCount int) error {
+	if proxyCount <= 0 {
+		return &InputError{"proxyCount", proxyCount, ErrNoProxies}
+	}
+	return nil
+}
+
+// validateConnections checks the number of connections each proxy may hold.
+func validateConnections(connectionCount int) error {
+	if connectionCount <= 0 {
+		return &InputError{"connectionCount", connectionCount, ErrNoConnections}
+	}
+	return nil
+}
+
+// validateWeights checks per-proxy weights.
+func validateWeights(weights []float64) error {
+	for _, w := range weights {
+		if w <= 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return &InputError{"weights", weights, ErrInvalidWeight}
+		}
+	}
+	return nil
+}
+
+// validateCapacities checks per-proxy capacities, at least one of which must be able
+// to hold a connection.
+func validateCapacities(capacities []int) error {
+	if len(capacities) == 0 {
+		return &InputError{"capacities", capacities, ErrNoProxies}
+	}
+
+	var total int
+	for _, c := range capacities {
+		if c < 0 {
+			return &InputError{"capacities", capacities, ErrInvalidCapacity}
+		}
+		total += c
+	}
+
+	if total == 0 {
+		return &InputError{"capacities", capacities, ErrNoConnections}
+	}
+	return nil
+}
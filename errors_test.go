@@ -0,0 +1,121 @@
+package crawlrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	second = time.Duration(1) * time.Second
+	minute = time.Duration(60) * time.Second
+)
+
+var validationTests = []struct {
+	name  string
+	f     func() error
+	err   error
+	input string
+}{
+	{"FixedDuration no keywords", func() error {
+		_, err := FixedDuration(0, 10, 45*second, 15*second, minute)
+		return err
+	}, ErrNoKeywords, "keywordCount"},
+	{"FixedDuration no proxies", func() error {
+		_, err := FixedDuration(100, 0, 45*second, 15*second, minute)
+		return err
+	}, ErrNoProxies, "proxyCount"},
+	{"FixedDuration no runtime", func() error {
+		_, err := FixedDuration(100, 10, 0, 15*second, minute)
+		return err
+	}, ErrInvalidRuntime, "avgJobRuntime"},
+	{"FixedDuration negative delay", func() error {
+		_, err := FixedDuration(100, 10, 45*second, -15*second, minute)
+		return err
+	}, ErrInvalidDelay, "minimumDelay"},
+	{"FixedDuration window", func() error {
+		_, err := FixedDuration(100, 10, 45*second, 15*second, 50*second)
+		return err
+	}, ErrInfeasibleWindow, ""},
+	{"FixedConnections no proxies", func() error {
+		_, err := FixedConnections(100, 0, 45*second, 15*second, 5)
+		return err
+	}, ErrNoProxies, "proxyCount"},
+	{"FixedConnections no connections", func() error {
+		_, err := FixedConnections(100, 10, 45*second, 15*second, 0)
+		return err
+	}, ErrNoConnections, "connectionCount"},
+	{"FixedThroughput no throughput", func() error {
+		_, err := FixedThroughput(100, 10, 45*second, 15*second, -1)
+		return err
+	}, ErrInvalidThroughput, "keywordsPerMinute"},
+	{"FixedDurationCapacities no proxies", func() error {
+		_, err := FixedDurationCapacities(100, nil, 45*second, 15*second, minute)
+		return err
+	}, ErrNoProxies, "capacities"},
+	{"FixedDurationCapacities negative capacity", func() error {
+		_, err := FixedDurationCapacities(100, []int{5, -1}, 45*second, 15*second, minute)
+		return err
+	}, ErrInvalidCapacity, "capacities"},
+	{"FixedConnectionsCapacities no capacity", func() error {
+		_, err := FixedConnectionsCapacities(100, []int{0, 0}, 45*second, 15*second)
+		return err
+	}, ErrNoConnections, "capacities"},
+	{"FixedDurationCapacities exceeded", func() error {
+		_, err := FixedDurationCapacities(100, []int{1, 1}, 45*second, 15*second, minute)
+		return err
+	}, ErrCapacityExceeded, ""},
+	{"FixedDurationDistribution empty", func() error {
+		_, err := FixedDurationDistribution(100, 10, RuntimeSamples{}, 0.95, 15*second, minute)
+		return err
+	}, ErrInvalidRuntime, "runtimes"},
+	{"FixedConnectionsDistribution confidence", func() error {
+		_, err := FixedConnectionsDistribution(100, 10, longTail, 0, 15*second, 5)
+		return err
+	}, ErrInvalidConfidence, "confidence"},
+	{"MinimumCost negative cost", func() error {
+		_, _, err := MinimumCost(100, 10, 45*second, 15*second, 10, minute, -1, 0)
+		return err
+	}, ErrInvalidCost, "proxyHourCost"},
+	{"MinimumCost deadline", func() error {
+		_, _, err := MinimumCost(100, 10, 45*second, 15*second, 10, 30*second, 1, 1)
+		return err
+	}, ErrMissedDeadline, ""},
+	{"MinimumProxies no connections", func() error {
+		_, _, err := MinimumProxies(100, 45*second, 15*second, 0, minute)
+		return err
+	}, ErrNoConnections, "connectionCount"},
+	{"Lookup unknown", func() error {
+		_, err := Lookup("unknown")
+		return err
+	}, ErrUnknownAlgorithm, ""},
+}
+
+func Test_ValidationErrors(t *testing.T) {
+	for _, tt := range validationTests {
+		err := tt.f()
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: exp: %v got: %v", tt.name, tt.err, err)
+			continue
+		}
+
+		var inputErr *InputError
+		if errors.As(err, &inputErr) != (tt.input != "") {
+			t.Errorf("%s: unexpected InputError: %v", tt.name, err)
+			continue
+		}
+		if inputErr != nil && inputErr.Input != tt.input {
+			t.Errorf("%s: Input exp: %s got: %s", tt.name, tt.input, inputErr.Input)
+		}
+	}
+}
+
+// FixedConnections previously divided by zero when there were fewer keywords than
+// proxies.
+func Test_FixedConnectionsFewerKeywordsThanProxies(t *testing.T) {
+	p, err := FixedConnections(3, 10, 45*second, 15*second, 5)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	comparePulse(t, 0, p, &Pulse{Volume: 1, Frequency: minute, Duration: minute})
+}
@@ -0,0 +1,115 @@
+package crawlrate
+
+import (
+	"math"
+	"time"
+)
+
+// Explanation breaks down how a Pulse was derived from its inputs.
+type Explanation struct {
+	Proxies             int           // Number of proxies the pulse is spread across
+	KeywordsPerProxy    int           // Keywords each proxy of weight 1 must process, rounded up
+	KeywordsPerChannel  int           // Keywords the busiest connection processes, one per tick
+	DelayIncrement      time.Duration // Extra delay added to the minimum delay to fill the time period
+	RowCapacity         int           // Most connections available across all proxies in a single tick
+	Capacity            int           // Connections available across all ticks, less any the cooldown or subnet limit hold back
+	UnusedCapacity      int           // Connections left unused in the last row holding keywords
+	UnusedPulseCapacity int           // Connections left unused across every tick of the pulse
+	Utilisation         float64       // Percentage of the available connections used
+	Slack               time.Duration // Time left unused between the end of the pulse and its time period, if it has one
+}
+
+// Explain reports how the pulse was derived from the inputs it was calculated with.
+// The connections are counted across the rows New plans, allowing for in.Cooldown and
+// in.SubnetLimit.
+func (p *Pulse) Explain(in PulseInputs) *Explanation {
+	e := &Explanation{Proxies: in.proxyCount()}
+
+	switch {
+	case p.Proxies > 0:
+		e.Proxies = p.Proxies
+	case len(p.Volumes) > 0:
+		e.Proxies = len(p.Volumes)
+	}
+
+	// Weighted proxies share the keywords by their total weight rather than
+	// their number.
+	totalWeight := float64(e.Proxies)
+	ws := in.weights()
+	if weighted(ws) {
+		totalWeight = 0
+		for _, w := range ws {
+			totalWeight += w
+		}
+	}
+	if totalWeight > 0 {
+		e.KeywordsPerProxy = int(math.Ceil(float64(in.KeywordCount) / totalWeight))
+	}
+
+	// The delay increment is whatever the frequency adds on top of the time
+	// each keyword takes, including its minimum delay.
+	runtime := in.AvgJobRuntime
+	if in.Runtimes != nil {
+		runtime = in.Runtimes.Quantile(in.Confidence)
+	}
+	if increment := p.Frequency - (runtime + in.MinimumDelay); increment > 0 {
+		e.DelayIncrement = increment
+	}
+
+	if p.Frequency <= 0 {
+		return e
+	}
+
+	// Count the connections of the rows New would plan, less those held back by
+	// the cooldown and the subnet limit.
+	if len(ws) != e.Proxies {
+		ws = normalise(make([]float64, e.Proxies))
+	}
+	volumes, _ := p.volumes(ws)
+	l, err := in.rowLimits(e.Proxies)
+	if err != nil {
+		l = rowLimits{}
+	}
+	rows := int((p.Duration + p.Frequency - 1) / p.Frequency)
+	grid := l.rowVolumes(volumes, rows, p.Frequency)
+
+	served := make([]int, e.Proxies)
+	var filled bool
+	for _, row := range grid {
+		var rowCapacity int
+		for proxy, v := range row {
+			rowCapacity += v
+			if v > 0 {
+				served[proxy]++
+			}
+		}
+		if rowCapacity > e.RowCapacity {
+			e.RowCapacity = rowCapacity
+		}
+		e.Capacity += rowCapacity
+
+		// Rows fill in order, so the last row holding keywords is the first
+		// to take the capacity past the keyword count.
+		if !filled && e.Capacity >= in.KeywordCount {
+			e.UnusedCapacity = e.Capacity - in.KeywordCount
+			filled = true
+		}
+	}
+	for _, n := range served {
+		if n > e.KeywordsPerChannel {
+			e.KeywordsPerChannel = n
+		}
+	}
+
+	if e.Capacity > 0 {
+		e.UnusedPulseCapacity = e.Capacity - in.KeywordCount
+		e.Utilisation = float64(in.KeywordCount) / float64(e.Capacity) * 100
+	}
+
+	// Only a pulse that must finish within a time period has any slack.
+	if p.Duration < p.TimePeriod {
+		e.Slack = p.TimePeriod - p.Duration
+	}
+
+	return e
+}
@@ -0,0 +1,65 @@
+package crawlrate
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var explainTests = []struct {
+	name string
+	in   PulseInputs
+	e    Explanation
+}{
+	// 100 keywords over 8 ticks of 10 proxies with 2 connections: 160 slots,
+	// 20s delay distributed as 2.5s per tick
+	{"duration", PulseInputs{KeywordCount: 100, ProxyCount: 10, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, TimePeriod: time.Duration(500) * time.Second},
+		Explanation{Proxies: 10, KeywordsPerProxy: 10, KeywordsPerChannel: 8, DelayIncrement: time.Duration(2500) * time.Millisecond, RowCapacity: 20, Capacity: 160, UnusedPulseCapacity: 60, Utilisation: 62.5}},
+
+	// 2 leftover milliseconds of slack
+	{"duration", PulseInputs{KeywordCount: 60, ProxyCount: 10, AvgJobRuntime: time.Duration(250) * time.Millisecond, MinimumDelay: time.Duration(50) * time.Millisecond, TimePeriod: time.Duration(2) * time.Second},
+		Explanation{Proxies: 10, KeywordsPerProxy: 6, KeywordsPerChannel: 6, DelayIncrement: time.Duration(33) * time.Millisecond, RowCapacity: 10, Capacity: 60, Utilisation: 100, Slack: time.Duration(2) * time.Millisecond}},
+
+	// The connections algorithm takes no time period, so has no slack
+	{"connections", PulseInputs{KeywordCount: 100, ProxyCount: 10, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, ConnectionCount: 3, TimePeriod: time.Duration(3600) * time.Second},
+		Explanation{Proxies: 10, KeywordsPerProxy: 10, KeywordsPerChannel: 4, RowCapacity: 30, Capacity: 120, UnusedCapacity: 20, UnusedPulseCapacity: 20, Utilisation: 100.0 / 120 * 100}},
+
+	// Per-proxy volumes
+	{"duration", PulseInputs{KeywordCount: 100, Capacities: []int{10, 5, 5}, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, TimePeriod: time.Duration(600) * time.Second},
+		Explanation{Proxies: 3, KeywordsPerProxy: 34, KeywordsPerChannel: 10, RowCapacity: 10, Capacity: 100, Utilisation: 100}},
+
+	// Proxies weighted 2:1:1 share 13 connections of a proxy of weight 1
+	{"duration", PulseInputs{KeywordCount: 100, Weights: []float64{2, 1, 1}, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, TimePeriod: time.Duration(120) * time.Second},
+		Explanation{Proxies: 3, KeywordsPerProxy: 25, KeywordsPerChannel: 2, RowCapacity: 52, Capacity: 104, UnusedCapacity: 4, UnusedPulseCapacity: 4, Utilisation: 100.0 / 104 * 100}},
+
+	// Proxies resting a tick after every 2 keywords leave the third tick empty
+	{"connections", PulseInputs{KeywordCount: 8, ProxyCount: 2, AvgJobRuntime: minute, ConnectionCount: 1, Cooldown: Cooldown{Requests: 2, Rest: minute}},
+		Explanation{Proxies: 2, KeywordsPerProxy: 4, KeywordsPerChannel: 4, RowCapacity: 2, Capacity: 8, Utilisation: 100}},
+
+	// A subnet of 3 proxies held to 2 connections a tick, alongside a subnet of 1
+	{"connections", PulseInputs{KeywordCount: 15, ProxyCount: 4, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, ConnectionCount: 2, SubnetLimit: SubnetLimit{24, 48, 2}, Proxies: append(generateProxies("10.0.0.", 3), generateProxies("10.0.1.", 1)...)},
+		Explanation{Proxies: 4, KeywordsPerProxy: 4, KeywordsPerChannel: 4, RowCapacity: 4, Capacity: 16, UnusedCapacity: 1, UnusedPulseCapacity: 1, Utilisation: 15.0 / 16 * 100}},
+
+	// Calculated proxies
+	{"proxies", PulseInputs{KeywordCount: 101, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, ConnectionCount: 5, TimePeriod: time.Duration(120) * time.Second},
+		Explanation{Proxies: 11, KeywordsPerProxy: 10, KeywordsPerChannel: 2, RowCapacity: 55, Capacity: 110, UnusedCapacity: 9, UnusedPulseCapacity: 9, Utilisation: 101.0 / 110 * 100}},
+}
+
+func Test_Explain(t *testing.T) {
+	for k, tt := range explainTests {
+		algorithm, _ := Lookup(tt.name)
+		p, err := algorithm.Pulse(tt.in)
+		if err != nil {
+			t.Errorf("Error test: %d Error: %s", k, err)
+			continue
+		}
+		e := *p.Explain(tt.in)
+		exp := tt.e
+		if math.Abs(e.Utilisation-exp.Utilisation) < 1e-9 {
+			e.Utilisation = exp.Utilisation
+		}
+		if e != exp {
+			t.Errorf("Error test: %d Explanation exp: %+v got: %+v", k, exp, e)
+		}
+	}
+}
@@ -10,11 +10,21 @@ import (
 // given the proxies and limiting them to a fixed number of connections.
 // If the user supplies a connectionCount greater than that required, then the returned pulse
 // will contain a corrected connectionCount (volume).
-func FixedConnections(keywordCount, proxyCount, avgJobRuntime, minimumDelay, connectionCount int) (*Pulse, error) {
+func FixedConnections(keywordCount, proxyCount int, avgJobRuntime, minimumDelay time.Duration, connectionCount int) (*Pulse, error) {
+
+	if err := validateJobs(keywordCount, avgJobRuntime, minimumDelay); err != nil {
+		return nil, err
+	}
+	if err := validateProxies(proxyCount); err != nil {
+		return nil, err
+	}
+	if err := validateConnections(connectionCount); err != nil {
+		return nil, err
+	}
 
 	// Divide the number of keywords between the number of proxies and round
 	// up. This gives us our keywordCountPerProxy.
-	keywordCountPerProxy := int(keywordCount / proxyCount)
+	keywordCountPerProxy := int(math.Ceil(float64(keywordCount) / float64(proxyCount)))
 
 	// Calculate the total number of keywords per channel
 	keywordsPerChannel := int(math.Ceil(float64(keywordCountPerProxy) / float64(connectionCount)))
@@ -28,11 +38,11 @@ func FixedConnections(keywordCount, proxyCount, avgJobRuntime, minimumDelay, con
 	totalKeywordTime := avgJobRuntime + minimumDelay
 
 	// Determine the total time period required to process all keywords
-	timePeriod := keywordsPerChannel * totalKeywordTime
+	timePeriod := time.Duration(keywordsPerChannel) * totalKeywordTime
 
 	return &Pulse{
 		Volume:    int(math.Ceil(float64(keywordCountPerProxy) / float64(keywordsPerChannel))),
-		Frequency: time.Duration(totalKeywordTime) * time.Second,
-		Duration:  time.Duration(timePeriod) * time.Second,
+		Frequency: totalKeywordTime,
+		Duration:  timePeriod,
 	}, nil
 }
@@ -6,36 +6,38 @@ import (
 )
 
 var fcTests = []struct {
-	keywordCount, proxyCount, avgJobRuntime, minimumDelay, connectionCount int
-	p                                                                      *Pulse
+	keywordCount, proxyCount    int
+	avgJobRuntime, minimumDelay time.Duration
+	connectionCount             int
+	p                           *Pulse
 }{
-	{10, 10, 45, 15, 1, &Pulse{1, time.Duration(60) * time.Second, time.Duration(60) * time.Second}},
-	{10, 5, 45, 15, 1, &Pulse{1, time.Duration(60) * time.Second, time.Duration(120) * time.Second}},
-	{10, 5, 45, 15, 2, &Pulse{2, time.Duration(60) * time.Second, time.Duration(60) * time.Second}},
+	{10, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
+	{10, 5, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{10, 5, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 2, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
 
 	// Even if the user supplies a larger connection count than is required, the pulse returned
 	// should return the actual (less than specified) connection count.
 
-	{10, 10, 45, 15, 2, &Pulse{1, time.Duration(60) * time.Second, time.Duration(60) * time.Second}},
-	{10, 10, 45, 15, 10, &Pulse{1, time.Duration(60) * time.Second, time.Duration(60) * time.Second}},
-	{10, 10, 45, 15, 100000, &Pulse{1, time.Duration(60) * time.Second, time.Duration(60) * time.Second}},
+	{10, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 2, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
+	{10, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 10, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
+	{10, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 100000, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
 
 	// Duration tests
-	{100, 10, 45, 15, 10, &Pulse{10, time.Duration(60) * time.Second, time.Duration(60) * time.Second}},
-	{100, 10, 45, 15, 9, &Pulse{5, time.Duration(60) * time.Second, time.Duration(120) * time.Second}},
-	{100, 10, 45, 15, 8, &Pulse{5, time.Duration(60) * time.Second, time.Duration(120) * time.Second}},
-	{100, 10, 45, 15, 7, &Pulse{5, time.Duration(60) * time.Second, time.Duration(120) * time.Second}},
-	{100, 10, 45, 15, 6, &Pulse{5, time.Duration(60) * time.Second, time.Duration(120) * time.Second}},
-	{100, 10, 45, 15, 5, &Pulse{5, time.Duration(60) * time.Second, time.Duration(120) * time.Second}},
-	{100, 10, 45, 15, 4, &Pulse{4, time.Duration(60) * time.Second, time.Duration(180) * time.Second}},
-	{100, 10, 45, 15, 3, &Pulse{3, time.Duration(60) * time.Second, time.Duration(240) * time.Second}},
-	{100, 10, 45, 15, 2, &Pulse{2, time.Duration(60) * time.Second, time.Duration(300) * time.Second}},
-	{100, 10, 45, 15, 1, &Pulse{1, time.Duration(60) * time.Second, time.Duration(600) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 10, &Pulse{Volume: 10, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 9, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 8, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 7, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 6, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 5, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 4, &Pulse{Volume: 4, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 3, &Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(240) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 2, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(300) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(600) * time.Second}},
 
 	// Frequency expansion tests
 	// keywordCount, proxyCount, avgJobRuntime, minimumDelay, connectionCount
 	// Volume, Frequency, Duration
-	// {100, 10, 45, 15, 10, &Pulse{10, time.Duration(60) * time.Second, time.Duration(60) * time.Second}},
+	// {100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 10, &Pulse{Volume: 10, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
 }
 
 func Test_FixedConnections(t *testing.T) {
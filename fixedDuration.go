@@ -1,7 +1,6 @@
 package crawlrate
 
 import (
-	"errors"
 	"math"
 	"time"
 )
@@ -9,7 +8,14 @@ import (
 // FixedDuration algorithm favours a fixed duration over a variable connection count per proxy.
 // This algorithm will tell you how many connections each proxy needs in order to complete
 // the keywords with the proxies provided within the fixed time duration.
-func FixedDuration(keywordCount, proxyCount, avgJobRuntime, minimumDelay, timePeriod int) (*Pulse, error) {
+func FixedDuration(keywordCount, proxyCount int, avgJobRuntime, minimumDelay, timePeriod time.Duration) (*Pulse, error) {
+
+	if err := validateJobs(keywordCount, avgJobRuntime, minimumDelay); err != nil {
+		return nil, err
+	}
+	if err := validateProxies(proxyCount); err != nil {
+		return nil, err
+	}
 
 	// Divide the number of keywords between the number of proxies and round
 	// up. This gives us our keywordCountPerProxy.
@@ -19,7 +25,7 @@ func FixedDuration(keywordCount, proxyCount, avgJobRuntime, minimumDelay, timePe
 	totalKeywordTime := avgJobRuntime + minimumDelay
 
 	if totalKeywordTime > timePeriod {
-		return nil, errors.New("total keyword time exceeds duration")
+		return nil, ErrInfeasibleWindow
 	}
 
 	// Determine the number of times we can fit the totalKeywordTime into our
@@ -33,9 +39,9 @@ func FixedDuration(keywordCount, proxyCount, avgJobRuntime, minimumDelay, timePe
 	timeRangeRemainder := timePeriod - (totalKeywordTime * keywordsPerChannel)
 
 	// Distribute the timeRangeRemainder over the minimumDelay. We round down
-	// here to ensure that the total combined totalKeywordTime is still less
-	// than our timeRange.
-	minimumDelayIncrement := timeRangeRemainder / keywordsPerChannel
+	// to the nearest millisecond here to ensure that the total combined
+	// totalKeywordTime is still less than our timeRange.
+	minimumDelayIncrement := (timeRangeRemainder / keywordsPerChannel).Truncate(time.Millisecond)
 
 	// Increment the totalKeywordTime to include our delay adjustment. This
 	// gives us the frequency of ticks.
@@ -47,8 +53,9 @@ func FixedDuration(keywordCount, proxyCount, avgJobRuntime, minimumDelay, timePe
 	// consequently, the number of keywords per tick we need to process per
 	// tick (volume).
 	return &Pulse{
-		Volume:    int(math.Ceil(float64(float64(keywordCountPerProxy) / float64(keywordsPerChannel)))),
-		Frequency: time.Duration(tickFrequency) * time.Second,
-		Duration:  time.Duration(tickFrequency*keywordsPerChannel) * time.Second,
+		Volume:     int(math.Ceil(float64(float64(keywordCountPerProxy) / float64(keywordsPerChannel)))),
+		Frequency:  tickFrequency,
+		Duration:   tickFrequency * keywordsPerChannel,
+		TimePeriod: timePeriod,
 	}, nil
 }
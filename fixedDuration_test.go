@@ -6,45 +6,52 @@ import (
 )
 
 var tests = []struct {
-	keywordCount, proxyCount, avgJobRuntime, minimumDelay, timePeriod int
-	p                                                                 *Pulse
+	keywordCount, proxyCount                int
+	avgJobRuntime, minimumDelay, timePeriod time.Duration
+	p                                       *Pulse
 }{
 	// NOTE:
 	// For ALL TESTS the Pulse Duration should match the timePeriod
 
 	// Volume tests
-	{100, 10, 45, 15, 60, &Pulse{10, time.Duration(60) * time.Second, time.Duration(60) * time.Second}},
-	{100, 10, 45, 15, 120, &Pulse{5, time.Duration(60) * time.Second, time.Duration(120) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(60) * time.Second, &Pulse{Volume: 10, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(120) * time.Second, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
 
 	// 30 keywords per minute for 4 minutes (last minute is 10 kwpm)
-	{100, 10, 45, 15, 240, &Pulse{3, time.Duration(60) * time.Second, time.Duration(240) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(240) * time.Second, &Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(240) * time.Second}},
 
 	// 15 kwpm for 3 minutes = 45 keywords: 15 proxies = 1 connection per proxy
-	{45, 15, 45, 15, 180, &Pulse{1, time.Duration(60) * time.Second, time.Duration(180) * time.Second}},
+	{45, 15, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(180) * time.Second, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}},
 
 	// 15 kwpm for 3 minutes = 45 keywords: 5 proxies = 3 connections per proxy
-	{45, 5, 45, 15, 180, &Pulse{3, time.Duration(60) * time.Second, time.Duration(180) * time.Second}},
+	{45, 5, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(180) * time.Second, &Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}},
 
 	// 15 kwpm for 3 minutes = 45 keywords: 2 proxies = 8 connections per proxy
-	{45, 2, 45, 15, 180, &Pulse{8, time.Duration(60) * time.Second, time.Duration(180) * time.Second}},
+	{45, 2, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(180) * time.Second, &Pulse{Volume: 8, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}},
 
 	// 13 kwpm for 8 minutes = 100 keywords: 10 proxies = 2 connections per proxy
-	{100, 10, 45, 15, 480, &Pulse{2, time.Duration(60) * time.Second, time.Duration(480) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(480) * time.Second, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(480) * time.Second}},
 
 	// Frequency expansion tests
-	{100, 10, 45, 15, 70, &Pulse{10, time.Duration(70) * time.Second, time.Duration(70) * time.Second}},
-	{100, 10, 45, 15, 140, &Pulse{5, time.Duration(70) * time.Second, time.Duration(140) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(70) * time.Second, &Pulse{Volume: 10, Frequency: time.Duration(70) * time.Second, Duration: time.Duration(70) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(140) * time.Second, &Pulse{Volume: 5, Frequency: time.Duration(70) * time.Second, Duration: time.Duration(140) * time.Second}},
 
 	// Algo for testing:
-	// 500s/60s = 8m18s (8m with 18s distributed)
+	// 500s/60s = 8m20s (8m with 20s distributed)
 	// 100 / 8m = 12.5 rounded up = 13 kwps
 	// 13 kwpm for 8m = 100 : 10 proxies = 2 connections per proxy
-	{100, 10, 45, 15, 500, &Pulse{2, time.Duration(62) * time.Second, time.Duration(496) * time.Second}},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(500) * time.Second, &Pulse{Volume: 2, Frequency: time.Duration(62500) * time.Millisecond, Duration: time.Duration(500) * time.Second}},
 
-	{4, 3, 60, 60, 300, &Pulse{1, time.Duration(150) * time.Second, time.Duration(300) * time.Second}},
+	{4, 3, time.Duration(60) * time.Second, time.Duration(60) * time.Second, time.Duration(300) * time.Second, &Pulse{Volume: 1, Frequency: time.Duration(150) * time.Second, Duration: time.Duration(300) * time.Second}},
 	
 	
-	{19, 3, 60, 0, 135, &Pulse{4, time.Duration(67) * time.Second, time.Duration(134) * time.Second}},
+	{19, 3, time.Duration(60) * time.Second, time.Duration(0) * time.Second, time.Duration(135) * time.Second, &Pulse{Volume: 4, Frequency: time.Duration(67500) * time.Millisecond, Duration: time.Duration(135) * time.Second}},
+
+	// Sub-second tests
+	{100, 10, time.Duration(450) * time.Millisecond, time.Duration(50) * time.Millisecond, time.Duration(2) * time.Second, &Pulse{Volume: 3, Frequency: time.Duration(500) * time.Millisecond, Duration: time.Duration(2) * time.Second}},
+
+	// 2000ms/300ms = 6 (200ms distributed as 33ms, rounded down to the millisecond)
+	{60, 10, time.Duration(250) * time.Millisecond, time.Duration(50) * time.Millisecond, time.Duration(2) * time.Second, &Pulse{Volume: 1, Frequency: time.Duration(333) * time.Millisecond, Duration: time.Duration(1998) * time.Millisecond}},
 }
 
 func Test_FixedDuration(t *testing.T) {
@@ -67,11 +74,12 @@ func Test_FixedDuration(t *testing.T) {
 }
 
 var errorTests = []struct {
-	keywordCount, proxyCount, avgJobRuntime, minimumDelay, timePeriod int
-	msg                                                               string
+	keywordCount, proxyCount                int
+	avgJobRuntime, minimumDelay, timePeriod time.Duration
+	msg                                     string
 }{
 	// Duration limit tests
-	{100, 10, 45, 15, 50, "total keyword time exceeds duration"},
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(50) * time.Second, "total keyword time exceeds duration"},
 }
 
 func Test_FixedDurationErrors(t *testing.T) {
@@ -0,0 +1,47 @@
+package crawlrate
+
+import (
+	"math"
+	"time"
+)
+
+// FixedThroughput algorithm favours a fixed crawl rate over a variable duration.
+// This algorithm will tell you how many connections each proxy needs, and how long
+// it will take to complete the keywords, in order to sustain the requested number
+// of keywords per minute (kwpm) across all of the proxies provided. The rate achieved
+// is rounded up to the nearest whole connection per proxy, so the returned pulse will
+// never crawl slower than requested.
+func FixedThroughput(keywordCount, proxyCount int, avgJobRuntime, minimumDelay time.Duration, keywordsPerMinute int) (*Pulse, error) {
+
+	if err := validateJobs(keywordCount, avgJobRuntime, minimumDelay); err != nil {
+		return nil, err
+	}
+	if err := validateProxies(proxyCount); err != nil {
+		return nil, err
+	}
+	if keywordsPerMinute <= 0 {
+		return nil, &InputError{"keywordsPerMinute", keywordsPerMinute, ErrInvalidThroughput}
+	}
+
+	// Calculate the total time a keyword will take, including its delay. This
+	// gives us the frequency of ticks.
+	totalKeywordTime := avgJobRuntime + minimumDelay
+
+	// Scale the requested rate to the length of a single tick. This gives us
+	// the number of keywords all proxies must process together per tick.
+	keywordsPerTick := float64(keywordsPerMinute) * totalKeywordTime.Minutes()
+
+	// Divide the keywords per tick between the number of proxies and round up.
+	// This is the number of connections required for each proxy (volume).
+	volume := int(math.Ceil(keywordsPerTick / float64(proxyCount)))
+
+	// Determine the number of ticks needed to process all keywords at that
+	// volume. We round up, the last tick may be partially filled.
+	ticks := int(math.Ceil(float64(keywordCount) / float64(volume*proxyCount)))
+
+	return &Pulse{
+		Volume:    volume,
+		Frequency: totalKeywordTime,
+		Duration:  time.Duration(ticks) * totalKeywordTime,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package crawlrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var ftTests = []struct {
+	keywordCount, proxyCount    int
+	avgJobRuntime, minimumDelay time.Duration
+	keywordsPerMinute           int
+	p                           *Pulse
+}{
+	// 10 kwpm across 5 proxies with 60s ticks = 2 connections per proxy
+	{30, 5, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 10, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}},
+
+	// Last tick is partially filled: 25 keywords at 10 kwpm takes 3 ticks
+	{25, 5, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 10, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}},
+
+	// 11 kwpm cannot be split evenly across 5 proxies, so round up to 3
+	// connections per proxy (15 kwpm)
+	{30, 5, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 11, &Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+
+	// 30s ticks: 20 kwpm is 10 keywords per tick = 1 connection per proxy
+	{100, 10, time.Duration(20) * time.Second, time.Duration(10) * time.Second, 20, &Pulse{Volume: 1, Frequency: time.Duration(30) * time.Second, Duration: time.Duration(300) * time.Second}},
+
+	// 120s ticks: 10 kwpm is 20 keywords per tick = 2 connections per proxy
+	{100, 10, time.Duration(60) * time.Second, time.Duration(60) * time.Second, 10, &Pulse{Volume: 2, Frequency: time.Duration(120) * time.Second, Duration: time.Duration(600) * time.Second}},
+
+	// Very low rates still require at least one connection per proxy
+	{10, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 1, &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
+}
+
+func Test_FixedThroughput(t *testing.T) {
+	for k, tt := range ftTests {
+		p, err := FixedThroughput(tt.keywordCount, tt.proxyCount, tt.avgJobRuntime, tt.minimumDelay, tt.keywordsPerMinute)
+		if err != nil {
+			t.Errorf("Error test: %d Error: %s", k, err)
+			continue
+		}
+		if p.Volume != tt.p.Volume {
+			t.Errorf("Error test: %d Volume exp: %d got: %d %v", k, tt.p.Volume, p.Volume, p)
+		}
+		if p.Frequency.Seconds() != tt.p.Frequency.Seconds() {
+			t.Errorf("Error test: %d Frequency exp: %f got: %f %v", k, tt.p.Frequency.Seconds(), p.Frequency.Seconds(), p)
+		}
+		if p.Duration.Seconds() != tt.p.Duration.Seconds() {
+			t.Errorf("Error test: %d Duration exp: %f got: %f %v", k, tt.p.Duration.Seconds(), p.Duration.Seconds(), p)
+		}
+	}
+}
+
+func Test_FixedThroughputErrors(t *testing.T) {
+	_, err := FixedThroughput(100, 10, time.Duration(45)*time.Second, time.Duration(15)*time.Second, 0)
+	if err == nil {
+		t.Errorf("Expected error")
+		return
+	}
+	if !errors.Is(err, ErrInvalidThroughput) {
+		t.Errorf("exp: %s got: %s", ErrInvalidThroughput, err)
+	}
+}
@@ -0,0 +1,45 @@
+package crawlrate
+
+import (
+	"math/rand"
+	"time"
+)
+
+// WithJitter offsets the start time of the rules in the crawl plan by a random
+// amount of up to max, so that the proxies do not all fire at the same instant
+// of a tick. The random offsets are drawn from seed, so the same inputs always
+// reproduce the same plan.
+//
+// Each connection of each proxy is given a single offset that it keeps for every
+// tick, so the time between the jobs on a connection is still the pulse frequency
+// and the minimum delay is never violated. The offset is kept within the tick.
+func WithJitter(max time.Duration, seed int64) Option {
+	return func(o *options) {
+		o.jitter = max
+		o.seed = seed
+	}
+}
+
+// offsets returns the jitter offset of each connection of each proxy, to
+// millisecond resolution, given the number of connections available for each
+// proxy. Offsets are never as long as the frequency, so a rule never leaves
+// its tick.
+func offsets(max time.Duration, seed int64, frequency time.Duration, volumes []int) [][]time.Duration {
+	if max > frequency {
+		max = frequency
+	}
+
+	ms := int64(max / time.Millisecond)
+	offs := make([][]time.Duration, len(volumes))
+	r := rand.New(rand.NewSource(seed))
+	for proxy, volume := range volumes {
+		offs[proxy] = make([]time.Duration, volume)
+		if ms <= 0 {
+			continue
+		}
+		for conn := range offs[proxy] {
+			offs[proxy][conn] = time.Duration(r.Int63n(ms)) * time.Millisecond
+		}
+	}
+	return offs
+}
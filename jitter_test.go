@@ -0,0 +1,90 @@
+package crawlrate
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_Jitter(t *testing.T) {
+	p := &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}
+	keywords := generateKeywords("keyword-", 15)
+	proxies := generateProxies("127.0.0.", 3)
+	max := time.Duration(10) * time.Second
+
+	cp, err := New(keywords, proxies, p, WithJitter(max, 42))
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+
+	// Offsets stay within the jitter and the tick, at millisecond resolution
+	channels := make(map[string][]time.Duration)
+	for k, r := range cp {
+		tick := r.Time / p.Frequency * p.Frequency
+		offset := r.Time - tick
+		if offset < 0 || offset >= max {
+			t.Errorf("Error test: %d offset %s outside jitter %s\n", k, offset, max)
+		}
+		if offset%time.Millisecond != 0 {
+			t.Errorf("Error test: %d offset %s is not whole milliseconds\n", k, offset)
+		}
+		key := fmt.Sprintf("%s/%d", r.Proxy, r.Conn)
+		channels[key] = append(channels[key], r.Time)
+	}
+
+	// Every job on a connection is still a whole frequency apart
+	for key, times := range channels {
+		for i := 1; i < len(times); i++ {
+			if times[i]-times[i-1] != p.Frequency {
+				t.Errorf("Error test: %s jobs %s apart, expected %s\n", key, times[i]-times[i-1], p.Frequency)
+			}
+		}
+	}
+
+	// The same seed reproduces the same plan
+	again, _ := New(keywords, proxies, p, WithJitter(max, 42))
+	if len(again) != len(cp) {
+		t.Fatalf("Expected %d rules. Got: %d\n", len(cp), len(again))
+	}
+	for k := range cp {
+		if again[k].Time != cp[k].Time || again[k].Keyword != cp[k].Keyword {
+			t.Errorf("Error test: %d expected %s. Got: %s\n", k, cp[k], again[k])
+		}
+	}
+
+	// A different seed gives a different plan
+	other, _ := New(keywords, proxies, p, WithJitter(max, 7))
+	same := true
+	for k := range cp {
+		if other[k].Time != cp[k].Time {
+			same = false
+		}
+	}
+	if same {
+		t.Errorf("Expected seed 7 to jitter differently to seed 42\n")
+	}
+}
+
+func Test_JitterWithinTick(t *testing.T) {
+	p := &Pulse{Volume: 1, Frequency: time.Duration(500) * time.Millisecond, Duration: time.Duration(1) * time.Second}
+
+	// A jitter longer than the frequency is kept within the tick
+	cp, err := New(generateKeywords("keyword-", 2), generateProxies("127.0.0.", 1), p, WithJitter(time.Duration(10)*time.Second, 1))
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+	for k, r := range cp {
+		tick := time.Duration(k) * p.Frequency
+		if r.Time < tick || r.Time >= tick+p.Frequency {
+			t.Errorf("Error test: %d expected start within [%s, %s). Got: %s\n", k, tick, tick+p.Frequency, r.Time)
+		}
+	}
+
+	// No jitter leaves the plan untouched
+	cp, _ = New(generateKeywords("keyword-", 2), generateProxies("127.0.0.", 1), p, WithJitter(0, 1))
+	for k, r := range cp {
+		if r.Time != time.Duration(k)*p.Frequency {
+			t.Errorf("Error test: %d expected %s. Got: %s\n", k, time.Duration(k)*p.Frequency, r.Time)
+		}
+	}
+}
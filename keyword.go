@@ -0,0 +1,69 @@
+package crawlrate
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keyword is a keyword to be crawled. Keywords of a higher priority are scheduled
+// in the earliest ticks of a plan, and when the pulse does not have the capacity
+// for every keyword it is those of the lowest priority that are left unscheduled.
+type Keyword struct {
+	Text     string
+	Priority int
+	Interval time.Duration // Time between crawls of the keyword by NewRecurring, 0 to crawl once
+	Target   Target        // Target to crawl the keyword against, if not fanned out WithTargets
+	Region   string        // Region of the proxies the keyword must be crawled through by NewRegional
+}
+
+func (k Keyword) String() string {
+	return k.Text
+}
+
+// ParseKeyword parses a line of a keyword file. A line holds the keyword, optionally
+// followed by tab separated fields for its priority, its recrawl interval and its
+// region e.g.
+//
+//	car insurance	10	1h	uk
+//	cheap car insurance		24h
+//
+// Keywords without a priority have a priority of 0, keywords without an interval are
+// crawled once and keywords without a region have no region.
+func ParseKeyword(line string) (Keyword, error) {
+	fields := strings.Split(line, "\t")
+	k := Keyword{Text: fields[0]}
+
+	if len(fields) > 1 && strings.TrimSpace(fields[1]) != "" {
+		priority, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return Keyword{}, &InputError{"priority", fields[1], ErrInvalidPriority}
+		}
+		k.Priority = priority
+	}
+
+	if len(fields) > 2 && strings.TrimSpace(fields[2]) != "" {
+		interval, err := time.ParseDuration(strings.TrimSpace(fields[2]))
+		if err != nil || interval < 0 {
+			return Keyword{}, &InputError{"interval", fields[2], ErrInvalidInterval}
+		}
+		k.Interval = interval
+	}
+
+	if len(fields) > 3 {
+		k.Region = strings.TrimSpace(fields[3])
+	}
+	return k, nil
+}
+
+// byPriority returns a copy of the keywords ordered by priority, highest first.
+// Keywords of equal priority keep the order they were given in.
+func byPriority(keywords []Keyword) []Keyword {
+	sorted := make([]Keyword, len(keywords))
+	copy(sorted, keywords)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+	return sorted
+}
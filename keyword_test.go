@@ -0,0 +1,76 @@
+package crawlrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var parseKeywordTests = []struct {
+	in  string
+	out Keyword
+	err error
+}{
+	{"car insurance", Keyword{Text: "car insurance"}, nil},
+	{"car insurance\t10", Keyword{Text: "car insurance", Priority: 10}, nil},
+	{"car insurance\t-1", Keyword{Text: "car insurance", Priority: -1}, nil},
+	{"car insurance\t 3 ", Keyword{Text: "car insurance", Priority: 3}, nil},
+	{"car insurance\t10\t1h", Keyword{Text: "car insurance", Priority: 10, Interval: time.Duration(3600) * time.Second}, nil},
+	{"car insurance\t\t30m", Keyword{Text: "car insurance", Interval: time.Duration(1800) * time.Second}, nil},
+	{"car insurance\thigh", Keyword{}, ErrInvalidPriority},
+	{"car insurance\t10\t1h\tuk", Keyword{Text: "car insurance", Priority: 10, Interval: time.Duration(3600) * time.Second, Region: "uk"}, nil},
+	{"car insurance\t\t\tus", Keyword{Text: "car insurance", Region: "us"}, nil},
+	{"car insurance\t1\tdaily", Keyword{}, ErrInvalidInterval},
+	{"car insurance\t1\t-1h", Keyword{}, ErrInvalidInterval},
+}
+
+func Test_ParseKeyword(t *testing.T) {
+	for k, tt := range parseKeywordTests {
+		kw, err := ParseKeyword(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Error test: %d err exp: %v got: %v\n", k, tt.err, err)
+		}
+		if kw != tt.out {
+			t.Errorf("Error test: %d exp: %+v got: %+v\n", k, tt.out, kw)
+		}
+	}
+}
+
+func Test_Priority(t *testing.T) {
+	// 2 ticks of 1 proxy with 1 connection only has room for 2 of 4 keywords
+	p := &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}
+	keywords := []Keyword{
+		{Text: "low", Priority: 1},
+		{Text: "high", Priority: 10},
+		{Text: "none"},
+		{Text: "medium", Priority: 5},
+	}
+
+	cp, err := New(keywords, generateProxies("127.0.0.", 1), p)
+	out := CrawlPlan{
+		{time.Duration(0) * time.Second, Proxy{}, 0, "high", Target{}},
+		{time.Duration(60) * time.Second, Proxy{}, 0, "medium", Target{}},
+	}
+	if len(cp) != len(out) {
+		t.Fatalf("Non-equal slice lengths. Got: %d Expected: %d\n", len(cp), len(out))
+	}
+	for k := range out {
+		if cp[k].Time != out[k].Time || cp[k].Keyword != out[k].Keyword {
+			t.Errorf("Error test: %d exp: %s got: %s\n", k, out[k], cp[k])
+		}
+	}
+
+	// The lowest priority keywords are the ones deferred
+	var unscheduled *UnscheduledError
+	if !errors.As(err, &unscheduled) {
+		t.Fatalf("Expected UnscheduledError. Got: %v\n", err)
+	}
+	if len(unscheduled.Keywords) != 2 || unscheduled.Keywords[0].Text != "low" || unscheduled.Keywords[1].Text != "none" {
+		t.Errorf("Unscheduled keywords wrong. Got: %v Expected: [low none]\n", unscheduled.Keywords)
+	}
+
+	// The keywords given are left in their original order
+	if keywords[0].Text != "low" {
+		t.Errorf("Expected keywords to be left unsorted. Got: %v\n", keywords)
+	}
+}
@@ -0,0 +1,101 @@
+package crawlrate
+
+import (
+	"math"
+	"time"
+)
+
+// Cost is the breakdown of what it costs to run a Pulse.
+type Cost struct {
+	ProxyCount     int     // Number of proxies rented
+	ProxyHours     float64 // Total proxy hours used to process all keywords
+	ProxyCost      float64 // Cost of the proxy hours
+	Connections    int     // Total number of connections held across all proxies
+	ConnectionCost float64 // Cost of the connections
+	Total          float64 // Total cost of the pulse
+}
+
+// MinimumCost algorithm favours the cheapest pulse that still finishes within a
+// deadline. This algorithm searches every combination of proxy count (up to
+// maxProxies) and connections per proxy (up to maxConnections), and returns the
+// cheapest pulse along with its cost breakdown. Proxies are charged per hour for
+// the duration of the pulse, and connections are charged per connection held.
+// Where two pulses cost the same, the one with the fewest proxies is returned.
+func MinimumCost(keywordCount, maxProxies int, avgJobRuntime, minimumDelay time.Duration, maxConnections int, deadline time.Duration, proxyHourCost, connectionCost float64) (*Pulse, *Cost, error) {
+
+	if err := validateJobs(keywordCount, avgJobRuntime, minimumDelay); err != nil {
+		return nil, nil, err
+	}
+	if err := validateProxies(maxProxies); err != nil {
+		return nil, nil, err
+	}
+	if err := validateConnections(maxConnections); err != nil {
+		return nil, nil, err
+	}
+	if proxyHourCost < 0 {
+		return nil, nil, &InputError{"proxyHourCost", proxyHourCost, ErrInvalidCost}
+	}
+	if connectionCost < 0 {
+		return nil, nil, &InputError{"connectionCost", connectionCost, ErrInvalidCost}
+	}
+
+	// Calculate the total time a keyword will take, including its delay. This
+	// gives us the frequency of ticks.
+	totalKeywordTime := avgJobRuntime + minimumDelay
+
+	// There is no point renting more proxies than there are keywords.
+	if maxProxies > keywordCount {
+		maxProxies = keywordCount
+	}
+
+	var (
+		best     *Pulse
+		bestCost *Cost
+	)
+
+	for proxyCount := 1; proxyCount <= maxProxies; proxyCount++ {
+		for connectionCount := 1; connectionCount <= maxConnections; connectionCount++ {
+
+			// Determine the number of ticks needed to process all keywords
+			// and discard the pulse if it overruns the deadline.
+			ticks := int(math.Ceil(float64(keywordCount) / float64(proxyCount*connectionCount)))
+			if time.Duration(ticks)*totalKeywordTime > deadline {
+				continue
+			}
+
+			// Correct the connection count down to that actually required to
+			// process all keywords in that number of ticks.
+			volume := int(math.Ceil(float64(keywordCount) / float64(proxyCount*ticks)))
+
+			p := &Pulse{
+				Volume:    volume,
+				Frequency: totalKeywordTime,
+				Duration:  time.Duration(ticks) * totalKeywordTime,
+			}
+			c := cost(p, proxyCount, proxyHourCost, connectionCost)
+
+			if bestCost == nil || c.Total < bestCost.Total {
+				best, bestCost = p, c
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, nil, ErrMissedDeadline
+	}
+
+	return best, bestCost, nil
+}
+
+// cost calculates the cost of running the pulse across proxyCount proxies.
+func cost(p *Pulse, proxyCount int, proxyHourCost, connectionCost float64) *Cost {
+	c := &Cost{
+		ProxyCount:  proxyCount,
+		ProxyHours:  float64(proxyCount) * p.Duration.Hours(),
+		Connections: proxyCount * p.Volume,
+	}
+	c.ProxyCost = c.ProxyHours * proxyHourCost
+	c.ConnectionCost = float64(c.Connections) * connectionCost
+	c.Total = c.ProxyCost + c.ConnectionCost
+	return c
+}
@@ -0,0 +1,74 @@
+package crawlrate
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var mcTests = []struct {
+	keywordCount, maxProxies      int
+	avgJobRuntime, minimumDelay   time.Duration
+	maxConnections                int
+	deadline                      time.Duration
+	proxyHourCost, connectionCost float64
+	p                             *Pulse
+	c                             *Cost
+}{
+	// The deadline allows at most 5 ticks, so at least 20 connections are
+	// needed. Fewer proxies means fewer proxy hours.
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 10, time.Duration(300) * time.Second, 1, 0.01,
+		&Pulse{Volume: 10, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(300) * time.Second},
+		&Cost{ProxyCount: 2, ProxyHours: 2.0 / 12, ProxyCost: 2.0 / 12, Connections: 20, ConnectionCost: 0.2, Total: 2.0/12 + 0.2},
+	},
+
+	// A single proxy cannot meet the deadline. Two and three proxies cost
+	// the same, so the fewest proxies win.
+	{19, 3, time.Duration(60) * time.Second, time.Duration(0) * time.Second, 4, time.Duration(180) * time.Second, 1, 0,
+		&Pulse{Volume: 4, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second},
+		&Cost{ProxyCount: 2, ProxyHours: 0.1, ProxyCost: 0.1, Connections: 8, ConnectionCost: 0, Total: 0.1},
+	},
+
+	// Expensive connections favour a longer pulse over more connections
+	{100, 10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 10, time.Duration(6000) * time.Second, 1, 10,
+		&Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(6000) * time.Second},
+		&Cost{ProxyCount: 1, ProxyHours: 100.0 / 60, ProxyCost: 100.0 / 60, Connections: 1, ConnectionCost: 10, Total: 100.0/60 + 10},
+	},
+}
+
+func Test_MinimumCost(t *testing.T) {
+	for k, tt := range mcTests {
+		p, c, err := MinimumCost(tt.keywordCount, tt.maxProxies, tt.avgJobRuntime, tt.minimumDelay, tt.maxConnections, tt.deadline, tt.proxyHourCost, tt.connectionCost)
+		if err != nil {
+			t.Errorf("Error test: %d Error: %s", k, err)
+			continue
+		}
+		comparePulse(t, k, p, tt.p)
+		if c.ProxyCount != tt.c.ProxyCount || c.Connections != tt.c.Connections {
+			t.Errorf("Error test: %d Cost exp: %+v got: %+v", k, tt.c, c)
+			continue
+		}
+		for _, f := range [][2]float64{
+			{c.ProxyHours, tt.c.ProxyHours},
+			{c.ProxyCost, tt.c.ProxyCost},
+			{c.ConnectionCost, tt.c.ConnectionCost},
+			{c.Total, tt.c.Total},
+		} {
+			if math.Abs(f[0]-f[1]) > 1e-9 {
+				t.Errorf("Error test: %d Cost exp: %+v got: %+v", k, tt.c, c)
+				break
+			}
+		}
+	}
+}
+
+func Test_MinimumCostErrors(t *testing.T) {
+	_, _, err := MinimumCost(100, 10, time.Duration(45)*time.Second, time.Duration(15)*time.Second, 10, time.Duration(30)*time.Second, 1, 1)
+	if err == nil {
+		t.Errorf("Expected error")
+		return
+	}
+	if err.Error() != "no pulse finishes within the deadline" {
+		t.Errorf("exp: %s got: %s", "no pulse finishes within the deadline", err.Error())
+	}
+}
@@ -0,0 +1,46 @@
+package crawlrate
+
+import (
+	"math"
+	"time"
+)
+
+// MinimumProxies algorithm favours the fewest proxies over a variable connection count.
+// This algorithm will tell you how many proxies are required to complete the keywords
+// within the fixed time duration, without exceeding connectionCount connections on any
+// proxy. The returned pulse is calculated for that number of proxies.
+func MinimumProxies(keywordCount int, avgJobRuntime, minimumDelay time.Duration, connectionCount int, timePeriod time.Duration) (int, *Pulse, error) {
+
+	if err := validateJobs(keywordCount, avgJobRuntime, minimumDelay); err != nil {
+		return 0, nil, err
+	}
+	if err := validateConnections(connectionCount); err != nil {
+		return 0, nil, err
+	}
+
+	// Calculate the total time a keyword will take, including its delay.
+	totalKeywordTime := avgJobRuntime + minimumDelay
+
+	if totalKeywordTime > timePeriod {
+		return 0, nil, ErrInfeasibleWindow
+	}
+
+	// Determine the number of times we can fit the totalKeywordTime into our
+	// time range. This gives us the total number of keywords we can fit into
+	// a channel for our timeRange. We round this figure down.
+	keywordsPerChannel := int(timePeriod / totalKeywordTime)
+
+	// Each proxy has connectionCount channels, so divide the keywords by the
+	// number of keywords a single proxy can process and round up. This is the
+	// number of proxies we need.
+	proxyCount := int(math.Ceil(float64(keywordCount) / float64(keywordsPerChannel*connectionCount)))
+
+	// The pulse itself is the fixed duration pulse for that many proxies.
+	p, err := FixedDuration(keywordCount, proxyCount, avgJobRuntime, minimumDelay, timePeriod)
+	if err != nil {
+		return 0, nil, err
+	}
+	p.Proxies = proxyCount
+
+	return proxyCount, p, nil
+}
@@ -0,0 +1,51 @@
+package crawlrate
+
+import (
+	"testing"
+	"time"
+)
+
+var mpTests = []struct {
+	keywordCount                int
+	avgJobRuntime, minimumDelay time.Duration
+	connectionCount             int
+	timePeriod                  time.Duration
+	proxyCount                  int
+	p                           *Pulse
+}{
+	// 2 ticks of 5 connections = 10 keywords per proxy
+	{100, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 5, time.Duration(120) * time.Second, 10, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Proxies: 10}},
+
+	// 101 keywords needs an extra proxy, the volume is corrected down
+	{101, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 5, time.Duration(120) * time.Second, 11, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Proxies: 11}},
+	{45, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 8, time.Duration(180) * time.Second, 2, &Pulse{Volume: 8, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second, Proxies: 2}},
+	{10, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 5, time.Duration(120) * time.Second, 1, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Proxies: 1}},
+
+	// Frequency expansion
+	{100, time.Duration(45) * time.Second, time.Duration(15) * time.Second, 10, time.Duration(70) * time.Second, 10, &Pulse{Volume: 10, Frequency: time.Duration(70) * time.Second, Duration: time.Duration(70) * time.Second, Proxies: 10}},
+}
+
+func Test_MinimumProxies(t *testing.T) {
+	for k, tt := range mpTests {
+		proxyCount, p, err := MinimumProxies(tt.keywordCount, tt.avgJobRuntime, tt.minimumDelay, tt.connectionCount, tt.timePeriod)
+		if err != nil {
+			t.Errorf("Error test: %d Error: %s", k, err)
+			continue
+		}
+		if proxyCount != tt.proxyCount || p.Proxies != tt.proxyCount {
+			t.Errorf("Error test: %d Proxies exp: %d got: %d (%d)", k, tt.proxyCount, proxyCount, p.Proxies)
+		}
+		comparePulse(t, k, p, tt.p)
+	}
+}
+
+func Test_MinimumProxiesErrors(t *testing.T) {
+	_, _, err := MinimumProxies(100, time.Duration(45)*time.Second, time.Duration(15)*time.Second, 5, time.Duration(50)*time.Second)
+	if err == nil {
+		t.Errorf("Expected error")
+		return
+	}
+	if err.Error() != "total keyword time exceeds duration" {
+		t.Errorf("exp: %s got: %s", "total keyword time exceeds duration", err.Error())
+	}
+}
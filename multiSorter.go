@@ -1,6 +1,7 @@
 package crawlrate
 
 import (
+	"net/netip"
 	"sort"
 )
 
@@ -19,35 +20,55 @@ Benefits of this solution:
 This is a far better solution; it is also far more performant than anything I've written so far!
 */
 
-type lessFunc func(p1, p2 *CrawlRule) bool
+// LessFunc reports whether the crawl rule p1 should sort before p2. Less functions
+// can be composed with OrderedBy, with each breaking the ties of the one before.
+type LessFunc func(p1, p2 *CrawlRule) bool
 
-// multiSorter implements the Sort interface, sorting the crawlRules within.
-type multiSorter struct {
+// MultiSorter implements the Sort interface, sorting the crawlRules within.
+type MultiSorter struct {
 	crawlRules CrawlPlan
-	less       []lessFunc
+	less       []LessFunc
 }
 
 // Sort sorts the argument slice according to the less functions passed to OrderedBy.
-func (ms *multiSorter) Sort(crawlRules CrawlPlan) {
+func (ms *MultiSorter) Sort(crawlRules CrawlPlan) {
 	ms.crawlRules = crawlRules
+
+	// Parse the address of each proxy once up front, rather than in every
+	// comparison of a large plan, and drop them again once sorted so the rules
+	// are left as they were given.
+	addrs := make(map[string]netip.Addr)
+	for i := range crawlRules {
+		p := &crawlRules[i].Proxy
+		addr, ok := addrs[p.Host]
+		if !ok {
+			addr = parseAddr(p.Host)
+			addrs[p.Host] = addr
+		}
+		p.addr, p.parsed = addr, true
+	}
 	sort.Sort(ms)
+	for i := range crawlRules {
+		p := &crawlRules[i].Proxy
+		p.addr, p.parsed = netip.Addr{}, false
+	}
 }
 
 // OrderedBy returns a Sorter that sorts using the less functions, in order.
 // Call its Sort method to sort the data.
-func orderedBy(less ...lessFunc) *multiSorter {
-	return &multiSorter{
+func OrderedBy(less ...LessFunc) *MultiSorter {
+	return &MultiSorter{
 		less: less,
 	}
 }
 
 // Len is part of sort.Interface.
-func (ms *multiSorter) Len() int {
+func (ms *MultiSorter) Len() int {
 	return len(ms.crawlRules)
 }
 
 // Swap is part of sort.Interface.
-func (ms *multiSorter) Swap(i, j int) {
+func (ms *MultiSorter) Swap(i, j int) {
 	ms.crawlRules[i], ms.crawlRules[j] = ms.crawlRules[j], ms.crawlRules[i]
 }
 
@@ -56,7 +77,11 @@ func (ms *multiSorter) Swap(i, j int) {
 // !Less. Note that it can call the less functions twice per call. We
 // could change the functions to return -1, 0, 1 and reduce the
 // number of calls for greater efficiency: an exercise for the reader.
-func (ms *multiSorter) Less(i, j int) bool {
+// With no less functions every rule is equal.
+func (ms *MultiSorter) Less(i, j int) bool {
+	if len(ms.less) == 0 {
+		return false
+	}
 	p, q := &ms.crawlRules[i], &ms.crawlRules[j]
 	// Try all but the last comparison.
 	var k int
@@ -76,3 +101,16 @@ func (ms *multiSorter) Less(i, j int) bool {
 	// the final comparison reports.
 	return ms.less[k](p, q)
 }
+
+// Reverse returns a less function that sorts in the opposite order to less e.g.
+// Reverse(ByProxy) stacks rules to the right rather than the left.
+func Reverse(less LessFunc) LessFunc {
+	return func(p1, p2 *CrawlRule) bool {
+		return less(p2, p1)
+	}
+}
+
+// Sort sorts the crawl plan using the less functions, in order.
+func (cp CrawlPlan) Sort(less ...LessFunc) {
+	OrderedBy(less...).Sort(cp)
+}
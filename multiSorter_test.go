@@ -0,0 +1,86 @@
+package crawlrate
+
+import (
+	"testing"
+	"time"
+)
+
+var unordered = CrawlPlan{
+	{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-4", Target{}},
+	{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-2", Target{}},
+	{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", Target{}},
+	{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.1"}, 0, "keyword-5", Target{}},
+	{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", Target{}},
+	{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.1"}, 1, "keyword-3", Target{}},
+}
+
+func byKeyword(c1, c2 *CrawlRule) bool {
+	return c1.Keyword < c2.Keyword
+}
+
+var orderingTests = []struct {
+	less []LessFunc
+	out  []string
+}{
+	// Left stacked
+	{[]LessFunc{ByStart, ByProxy, ByIncreasingConnections}, []string{"keyword-0", "keyword-1", "keyword-2", "keyword-3", "keyword-4", "keyword-5"}},
+
+	// Right stacked
+	{[]LessFunc{ByStart, Reverse(ByProxy), ByIncreasingConnections}, []string{"keyword-2", "keyword-3", "keyword-0", "keyword-1", "keyword-5", "keyword-4"}},
+
+	{[]LessFunc{ByStart, ByProxy, ByDecreasingConnections}, []string{"keyword-1", "keyword-0", "keyword-3", "keyword-2", "keyword-4", "keyword-5"}},
+	{[]LessFunc{ByProxy, ByStart, ByIncreasingConnections}, []string{"keyword-0", "keyword-1", "keyword-4", "keyword-2", "keyword-3", "keyword-5"}},
+
+	// Custom less functions compose with the built in ones
+	{[]LessFunc{Reverse(ByStart), byKeyword}, []string{"keyword-4", "keyword-5", "keyword-0", "keyword-1", "keyword-2", "keyword-3"}},
+}
+
+func Test_Ordering(t *testing.T) {
+	for k, tt := range orderingTests {
+		cp := make(CrawlPlan, len(unordered))
+		copy(cp, unordered)
+		cp.Sort(tt.less...)
+
+		for n := range cp {
+			if cp[n].Keyword != tt.out[n] {
+				t.Errorf("Test %d: Keyword %d not equal. Got: %s Expected: %s\n", k, n, cp[n].Keyword, tt.out[n])
+			}
+		}
+	}
+}
+
+func Test_WithOrdering(t *testing.T) {
+	p := &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}
+	cp, err := New(generateKeywords("keyword-", 4), generateProxies("127.0.0.", 2), p, WithOrdering(ByStart, Reverse(ByProxy), ByDecreasingConnections))
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+
+	exp := []string{"keyword-3", "keyword-2", "keyword-1", "keyword-0"}
+	for n := range cp {
+		if cp[n].Keyword != exp[n] {
+			t.Errorf("Keyword %d not equal. Got: %s Expected: %s\n", n, cp[n].Keyword, exp[n])
+		}
+	}
+}
+
+func Test_OrderingNoLessFuncs(t *testing.T) {
+	cp := make(CrawlPlan, len(unordered))
+	copy(cp, unordered)
+	cp.Sort()
+	OrderedBy().Sort(cp)
+
+	p := &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}
+	cp, err := New(generateKeywords("keyword-", 4), generateProxies("127.0.0.", 2), p, WithOrdering())
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+
+	// The default ordering is kept
+	exp := []string{"keyword-0", "keyword-1", "keyword-2", "keyword-3"}
+	for n := range cp {
+		if cp[n].Keyword != exp[n] {
+			t.Errorf("Keyword %d not equal. Got: %s Expected: %s\n", n, cp[n].Keyword, exp[n])
+		}
+	}
+}
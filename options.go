@@ -0,0 +1,195 @@
+package crawlrate
+
+import "time"
+
+// Option configures how New builds a crawl plan.
+type Option func(*options)
+
+type options struct {
+	weighting Weighting
+	lastRow   LastRowStrategy
+	ordering  []LessFunc
+	jitter    time.Duration
+	seed      int64
+	affinity  bool
+	targets   []Target
+	cooldown  Cooldown
+	subnets   SubnetLimit
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		weighting: BottomHeavyWeighting,
+		lastRow:   ClusterLastRow,
+		ordering:  []LessFunc{ByStart, ByProxy, ByIncreasingConnections},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Weighting determines which end of the pulse a crawl plan favours, and so where
+// its partial row falls.
+type Weighting int
+
+const (
+	// BottomHeavyWeighting plans full rows as soon as possible, leaving the
+	// partial row at the end of the pulse.
+	BottomHeavyWeighting Weighting = iota
+
+	// TopHeavyWeighting plans full rows at the end of the pulse, leaving the
+	// partial row at the start.
+	TopHeavyWeighting
+
+	// EvenWeighting spreads the keywords as evenly as possible across every
+	// row of the pulse, so each row holds roughly keywords/rows keywords
+	// and the request rate stays flat.
+	EvenWeighting
+)
+
+// WithWeighting sets the weighting of the crawl plan. Plans are bottom heavy by
+// default.
+func WithWeighting(w Weighting) Option {
+	return func(o *options) {
+		o.weighting = w
+	}
+}
+
+// WithOrdering sets the order of the rules in the crawl plan, using the less
+// functions in turn. Plans are ordered by start time, proxy and increasing
+// connection by default, and WithOrdering with no less functions keeps that order.
+func WithOrdering(less ...LessFunc) Option {
+	return func(o *options) {
+		if len(less) > 0 {
+			o.ordering = less
+		}
+	}
+}
+
+// rowCounts returns the number of keywords to schedule in each of the rows of the
+// pulse, given the number of connections available in each row.
+func (w Weighting) rowCounts(keywordCount int, capacities []int) []int {
+	rows := len(capacities)
+	counts := make([]int, rows)
+
+	switch w {
+	case EvenWeighting:
+		// Give every row with room to spare the same share, with the
+		// remainder going one apiece to the earliest rows. Rows that fill up
+		// leave their share to be spread across the rest in the next round.
+		for keywordCount > 0 {
+			var open []int
+			for row := range counts {
+				if counts[row] < capacities[row] {
+					open = append(open, row)
+				}
+			}
+			if len(open) == 0 {
+				break
+			}
+
+			share, remainder := keywordCount/len(open), keywordCount%len(open)
+			for i, row := range open {
+				want := share
+				if i < remainder {
+					want++
+				}
+				n := fill(&want, capacities[row]-counts[row])
+				counts[row] += n
+				keywordCount -= n
+			}
+		}
+
+	case TopHeavyWeighting:
+		// Fill rows from the end of the pulse, so the partial row is the
+		// earliest one used.
+		for row := rows - 1; row >= 0 && keywordCount > 0; row-- {
+			counts[row] = fill(&keywordCount, capacities[row])
+		}
+
+	default:
+		for row := 0; row < rows && keywordCount > 0; row++ {
+			counts[row] = fill(&keywordCount, capacities[row])
+		}
+	}
+
+	return counts
+}
+
+// fill takes as many of the remaining keywords as fit into capacity.
+func fill(remaining *int, capacity int) int {
+	n := capacity
+	if *remaining < n {
+		n = *remaining
+	}
+	*remaining -= n
+	return n
+}
+
+// LastRowStrategy determines how the connections of a partial row, one that holds
+// fewer keywords than the proxies have connections for, are distributed across the
+// proxies. A plan with a single row has no full rows to balance against, so if that
+// row is partial the strategy applies to it just the same.
+type LastRowStrategy int
+
+const (
+	// ClusterLastRow packs the connections onto as few proxies as possible,
+	// giving the remaining proxies a reprieve e.g.
+	//
+	//	120s 2 2 1
+	ClusterLastRow LastRowStrategy = iota
+
+	// SpreadLastRow reduces the connections evenly across all of the proxies
+	// e.g.
+	//
+	//	120s 1 1 1 1 1
+	SpreadLastRow
+)
+
+// WithLastRow sets how the partial row of the crawl plan is distributed. Partial
+// rows are clustered by default.
+func WithLastRow(s LastRowStrategy) Option {
+	return func(o *options) {
+		o.lastRow = s
+	}
+}
+
+// cell is a single connection of a single proxy within a row.
+type cell struct {
+	proxy, conn int
+}
+
+// cells returns the connections that hold the count keywords of a row, given the
+// number of connections available for each proxy, in the order keywords fill them.
+// Full rows are always filled a proxy at a time.
+func (s LastRowStrategy) cells(count int, volumes []int) []cell {
+	cs := make([]cell, 0, count)
+
+	var rowCapacity int
+	for _, volume := range volumes {
+		rowCapacity += volume
+	}
+
+	switch {
+	case s == SpreadLastRow && count < rowCapacity:
+		// Take one connection from each proxy in turn.
+		for conn, max := 0, maxVolume(volumes); conn < max && len(cs) < count; conn++ {
+			for proxy, volume := range volumes {
+				if conn < volume && len(cs) < count {
+					cs = append(cs, cell{proxy, conn})
+				}
+			}
+		}
+
+	default:
+		// Take every connection from each proxy in turn.
+		for proxy, volume := range volumes {
+			for conn := 0; conn < volume && len(cs) < count; conn++ {
+				cs = append(cs, cell{proxy, conn})
+			}
+		}
+	}
+
+	return cs
+}
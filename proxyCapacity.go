@@ -0,0 +1,128 @@
+package crawlrate
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// FixedDurationCapacities algorithm is the FixedDuration algorithm for a pool of proxies
+// that can each safely hold a different number of connections. capacities holds the
+// maximum number of connections for each proxy. The returned pulse contains a volume
+// for each individual proxy, proportional to its capacity.
+func FixedDurationCapacities(keywordCount int, capacities []int, avgJobRuntime, minimumDelay, timePeriod time.Duration) (*Pulse, error) {
+
+	if err := validateCapacities(capacities); err != nil {
+		return nil, err
+	}
+
+	// Treat the whole pool as a single proxy. This gives us the frequency and
+	// duration, and the total number of connections required per tick.
+	p, err := FixedDuration(keywordCount, 1, avgJobRuntime, minimumDelay, timePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	// Share the total number of connections between the proxies.
+	if p.Volumes, err = distributeVolume(p.Volume, capacities); err != nil {
+		return nil, err
+	}
+	p.Volume = maxVolume(p.Volumes)
+
+	return p, nil
+}
+
+// FixedConnectionsCapacities algorithm is the FixedConnections algorithm for a pool of
+// proxies that can each safely hold a different number of connections. capacities holds
+// the maximum number of connections for each proxy. The returned pulse contains a volume
+// for each individual proxy, proportional to its capacity, and corrected down to the
+// number of connections actually required.
+func FixedConnectionsCapacities(keywordCount int, capacities []int, avgJobRuntime, minimumDelay time.Duration) (*Pulse, error) {
+
+	if err := validateCapacities(capacities); err != nil {
+		return nil, err
+	}
+
+	// The total number of connections available across the pool per tick.
+	var totalCapacity int
+	for _, c := range capacities {
+		totalCapacity += c
+	}
+
+	// Treat the whole pool as a single proxy using every available
+	// connection. This gives us the frequency and duration, and the corrected
+	// total number of connections required per tick.
+	p, err := FixedConnections(keywordCount, 1, avgJobRuntime, minimumDelay, totalCapacity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Share the total number of connections between the proxies.
+	if p.Volumes, err = distributeVolume(p.Volume, capacities); err != nil {
+		return nil, err
+	}
+	p.Volume = maxVolume(p.Volumes)
+
+	return p, nil
+}
+
+// distributeVolume shares total connections between proxies in proportion to their
+// capacity, using the largest remainder method. No proxy is given more connections
+// than its capacity.
+func distributeVolume(total int, capacities []int) ([]int, error) {
+	var totalCapacity int
+	for _, c := range capacities {
+		totalCapacity += c
+	}
+
+	if total > totalCapacity {
+		return nil, ErrCapacityExceeded
+	}
+
+	weights := make([]float64, len(capacities))
+	for i, c := range capacities {
+		weights[i] = float64(c)
+	}
+	return shareVolume(total, weights), nil
+}
+
+// shareVolume shares total connections between proxies in proportion to their
+// weights, using the largest remainder method.
+func shareVolume(total int, weights []float64) []int {
+	var totalWeight float64
+	for _, w := range weights {
+		totalWeight += w
+	}
+
+	volumes := make([]int, len(weights))
+	fractions := make([]float64, len(weights))
+	order := make([]int, len(weights))
+	allocated := 0
+	for i, w := range weights {
+		share := float64(total) * w / totalWeight
+		volumes[i] = int(math.Floor(share))
+		fractions[i] = share - math.Floor(share)
+		order[i] = i
+		allocated += volumes[i]
+	}
+
+	// Hand out the connections lost to rounding down, largest fractional
+	// share first.
+	sort.SliceStable(order, func(a, b int) bool {
+		return fractions[order[a]] > fractions[order[b]]
+	})
+	for _, i := range order[:total-allocated] {
+		volumes[i]++
+	}
+
+	return volumes
+}
+
+func maxVolume(volumes []int) (max int) {
+	for _, v := range volumes {
+		if v > max {
+			max = v
+		}
+	}
+	return
+}
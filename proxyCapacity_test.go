@@ -0,0 +1,115 @@
+package crawlrate
+
+import (
+	"testing"
+	"time"
+)
+
+var fdcTests = []struct {
+	keywordCount                            int
+	capacities                              []int
+	avgJobRuntime, minimumDelay, timePeriod time.Duration
+	p                                       *Pulse
+}{
+	// 10 connections per tick shared 2:1:1
+	{100, []int{10, 5, 5}, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(600) * time.Second, &Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(600) * time.Second, Volumes: []int{5, 3, 2}}},
+
+	// 4 connections per tick shared 2:1
+	{12, []int{4, 2}, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(180) * time.Second, &Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second, Volumes: []int{3, 1}}},
+
+	// Proxies without any capacity are never used
+	{12, []int{4, 0, 2}, time.Duration(45) * time.Second, time.Duration(15) * time.Second, time.Duration(180) * time.Second, &Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second, Volumes: []int{3, 0, 1}}},
+}
+
+func Test_FixedDurationCapacities(t *testing.T) {
+	for k, tt := range fdcTests {
+		p, err := FixedDurationCapacities(tt.keywordCount, tt.capacities, tt.avgJobRuntime, tt.minimumDelay, tt.timePeriod)
+		if err != nil {
+			t.Errorf("Error test: %d Error: %s", k, err)
+			continue
+		}
+		comparePulse(t, k, p, tt.p)
+	}
+}
+
+var fccTests = []struct {
+	keywordCount                int
+	capacities                  []int
+	avgJobRuntime, minimumDelay time.Duration
+	p                           *Pulse
+}{
+	{100, []int{10, 5, 5}, time.Duration(45) * time.Second, time.Duration(15) * time.Second, &Pulse{Volume: 10, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(300) * time.Second, Volumes: []int{10, 5, 5}}},
+
+	// Connections are corrected down to those actually required
+	{90, []int{10, 5, 5}, time.Duration(45) * time.Second, time.Duration(15) * time.Second, &Pulse{Volume: 9, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(300) * time.Second, Volumes: []int{9, 5, 4}}},
+	{10, []int{4, 1}, time.Duration(45) * time.Second, time.Duration(15) * time.Second, &Pulse{Volume: 4, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Volumes: []int{4, 1}}},
+}
+
+func Test_ShareVolume(t *testing.T) {
+	tests := []struct {
+		total   int
+		weights []float64
+		out     []int
+	}{
+		{7, []float64{2, 1, 0.5}, []int{4, 2, 1}},
+		{10, []float64{1, 1, 1}, []int{4, 3, 3}},
+		{5, []float64{3, 1}, []int{4, 1}},
+		{0, []float64{1, 2}, []int{0, 0}},
+	}
+	for k, tt := range tests {
+		volumes := shareVolume(tt.total, tt.weights)
+		for i := range tt.out {
+			if volumes[i] != tt.out[i] {
+				t.Errorf("Error test: %d exp: %v got: %v\n", k, tt.out, volumes)
+				break
+			}
+		}
+	}
+}
+
+func Test_FixedConnectionsCapacities(t *testing.T) {
+	for k, tt := range fccTests {
+		p, err := FixedConnectionsCapacities(tt.keywordCount, tt.capacities, tt.avgJobRuntime, tt.minimumDelay)
+		if err != nil {
+			t.Errorf("Error test: %d Error: %s", k, err)
+			continue
+		}
+		comparePulse(t, k, p, tt.p)
+	}
+}
+
+func Test_FixedDurationCapacitiesErrors(t *testing.T) {
+	_, err := FixedDurationCapacities(100, []int{1, 1}, time.Duration(45)*time.Second, time.Duration(15)*time.Second, time.Duration(60)*time.Second)
+	if err == nil {
+		t.Errorf("Expected error")
+		return
+	}
+	if err.Error() != "proxy capacity exceeded" {
+		t.Errorf("exp: %s got: %s", "proxy capacity exceeded", err.Error())
+	}
+}
+
+func comparePulse(t *testing.T, k int, got, exp *Pulse) {
+	if got.Volume != exp.Volume {
+		t.Errorf("Error test: %d Volume exp: %d got: %d %v", k, exp.Volume, got.Volume, got)
+	}
+	if got.Frequency.Seconds() != exp.Frequency.Seconds() {
+		t.Errorf("Error test: %d Frequency exp: %f got: %f %v", k, exp.Frequency.Seconds(), got.Frequency.Seconds(), got)
+	}
+	if got.Duration.Seconds() != exp.Duration.Seconds() {
+		t.Errorf("Error test: %d Duration exp: %f got: %f %v", k, exp.Duration.Seconds(), got.Duration.Seconds(), got)
+	}
+	if got.Proxies != exp.Proxies {
+		t.Errorf("Error test: %d Proxies exp: %d got: %d %v", k, exp.Proxies, got.Proxies, got)
+	}
+	if len(got.Volumes) != len(exp.Volumes) {
+		t.Errorf("Error test: %d Volumes exp: %v got: %v", k, exp.Volumes, got.Volumes)
+		return
+	}
+	for i := range exp.Volumes {
+		if got.Volumes[i] != exp.Volumes[i] {
+			t.Errorf("Error test: %d Volumes exp: %v got: %v", k, exp.Volumes, got.Volumes)
+			break
+		}
+	}
+}
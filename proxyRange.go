@@ -0,0 +1,227 @@
+package crawlrate
+
+import (
+	"math/big"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+)
+
+// DefaultRangeLimit is the most proxies a range expands into when no limit is given.
+const DefaultRangeLimit = 4096
+
+// RangeLimit limits the number of proxies a range of addresses expands into, so
+// that a large range such as an IPv6 /64 does not produce an unworkable plan.
+type RangeLimit struct {
+	Max    int   // Most proxies a range expands into, or 0 for DefaultRangeLimit
+	Sample bool  // Sample Max addresses from a larger range, rather than fail
+	Seed   int64 // Seed of the sample, so the same inputs always give the same proxies
+}
+
+// ExpandProxy parses a proxy as ParseProxy does, except that the host may be a range
+// of IPv4 or IPv6 addresses given in CIDR notation or as first-last e.g.
+//
+//	10.0.0.0/24
+//	10.0.0.1-10.0.0.20:3128
+//	http://user:pass@[2001:db8::/64]:8080
+//
+// A range expands into a proxy for each address, in address order, sharing the
+// scheme, port and credentials. The network and broadcast addresses of an IPv4 CIDR
+// are left out. A range holding more addresses than limit.Max is an error, unless
+// limit.Sample is set, in which case limit.Max addresses are sampled at random from
+// across the range. The addresses of a range are never enumerated beyond those
+// returned, so a sample may be taken from a range of any size.
+func ExpandProxy(s string, limit RangeLimit) ([]Proxy, error) {
+	prefix, host, suffix := splitProxy(s)
+	first, size, v4, err := parseRange(host)
+	if err != nil {
+		return nil, &InputError{"proxy", redact(s), err}
+	}
+	if first == nil {
+		p, err := ParseProxy(s)
+		if err != nil {
+			return nil, err
+		}
+		return []Proxy{p}, nil
+	}
+
+	max := limit.Max
+	if max <= 0 {
+		max = DefaultRangeLimit
+	}
+
+	var offsets []*big.Int
+	switch {
+	case size.Cmp(big.NewInt(int64(max))) <= 0:
+		for i := int64(0); i < size.Int64(); i++ {
+			offsets = append(offsets, big.NewInt(i))
+		}
+	case !limit.Sample:
+		return nil, &InputError{"proxy", redact(s), ErrRangeTooLarge}
+	default:
+		offsets = sample(size, max, limit.Seed)
+	}
+
+	proxies := make([]Proxy, 0, len(offsets))
+	for _, offset := range offsets {
+		h := addressAt(first, offset, v4).String()
+		if !v4 {
+			h = "[" + h + "]"
+		}
+		p, err := ParseProxy(prefix + h + suffix)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, p)
+	}
+	return proxies, nil
+}
+
+// splitProxy splits a proxy into its host and whatever precedes and follows it,
+// without parsing the host, which may be a range. The path, query and fragment of a
+// URL are dropped, as ParseProxy ignores them.
+func splitProxy(s string) (prefix, host, suffix string) {
+	rest := s
+	if i := strings.Index(rest, "://"); i >= 0 {
+		prefix, rest = rest[:i+3], trimPath(rest[i+3:])
+	}
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		prefix, rest = prefix+rest[:i+1], rest[i+1:]
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "["):
+		if i := strings.Index(rest, "]"); i >= 0 {
+			return prefix, rest[1:i], rest[i+1:]
+		}
+	case strings.Count(rest, ":") == 1:
+		i := strings.Index(rest, ":")
+		return prefix, rest[:i], rest[i:]
+	}
+	return prefix, rest, ""
+}
+
+// trimPath drops the path, query and fragment that follow the host and port of a URL.
+// The "/" of a range in CIDR notation, bracketed or following an address, is kept.
+func trimPath(s string) string {
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+
+	// Skip past the userinfo and host to where a path could start.
+	from := strings.LastIndex(s, "@") + 1
+	if strings.HasPrefix(s[from:], "[") {
+		if i := strings.Index(s[from:], "]"); i >= 0 {
+			from += i
+		}
+	} else if i := strings.Index(s[from:], "/"); i >= 0 && isPrefixLength(s[from:from+i], s[from+i+1:]) {
+		from += i + 1
+	}
+
+	if i := strings.Index(s[from:], "/"); i >= 0 {
+		return s[:from+i]
+	}
+	return s
+}
+
+// isPrefixLength reports whether the text following a "/" after host is the prefix
+// length of a CIDR range, rather than the path of a URL.
+func isPrefixLength(host, after string) bool {
+	if net.ParseIP(host) == nil {
+		return false
+	}
+	n := 0
+	for n < len(after) && after[n] >= '0' && after[n] <= '9' {
+		n++
+	}
+	return n > 0 && (n == len(after) || after[n] == ':' || after[n] == '/')
+}
+
+// parseRange parses a range of addresses in CIDR notation or as first-last, returning
+// the first address and the number of addresses. first is nil if host is not a range.
+func parseRange(host string) (first, size *big.Int, v4 bool, err error) {
+	if strings.Contains(host, "/") {
+		_, network, err := net.ParseCIDR(host)
+		if err != nil {
+			return nil, nil, false, ErrInvalidProxy
+		}
+		ones, bits := network.Mask.Size()
+		v4 = bits == 32
+		first = new(big.Int).SetBytes(network.IP)
+		size = new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+
+		// Leave out the network and broadcast addresses of an IPv4 network
+		// that has them.
+		if v4 && ones <= 30 {
+			first.Add(first, big.NewInt(1))
+			size.Sub(size, big.NewInt(2))
+		}
+		return first, size, v4, nil
+	}
+
+	// A hostname may hold a hyphen, so only a hyphen between two addresses
+	// makes a range.
+	i := strings.Index(host, "-")
+	if i < 0 {
+		return nil, nil, false, nil
+	}
+	from, to := net.ParseIP(host[:i]), net.ParseIP(host[i+1:])
+	if from == nil || to == nil {
+		return nil, nil, false, nil
+	}
+	if (from.To4() == nil) != (to.To4() == nil) {
+		return nil, nil, false, ErrInvalidProxy
+	}
+	v4 = from.To4() != nil
+	if v4 {
+		from, to = from.To4(), to.To4()
+	}
+	first, last := new(big.Int).SetBytes(from), new(big.Int).SetBytes(to)
+	if last.Cmp(first) < 0 {
+		return nil, nil, false, ErrInvalidProxy
+	}
+	size = new(big.Int).Sub(last, first)
+	return first, size.Add(size, big.NewInt(1)), v4, nil
+}
+
+// addressAt returns the address offset from first.
+func addressAt(first, offset *big.Int, v4 bool) net.IP {
+	n := 16
+	if v4 {
+		n = 4
+	}
+	b := new(big.Int).Add(first, offset).Bytes()
+	ip := make(net.IP, n)
+	copy(ip[n-len(b):], b)
+	return ip
+}
+
+// sample returns n distinct offsets into a range of size addresses, drawn at random
+// from seed, in ascending order. n must be less than size.
+func sample(size *big.Int, n int, seed int64) []*big.Int {
+	r := rand.New(rand.NewSource(seed))
+	var offsets []*big.Int
+
+	if size.Cmp(big.NewInt(int64(2*n))) <= 0 {
+		// The range is small enough to shuffle, which avoids drawing the same
+		// offset again and again as the sample fills it.
+		for _, i := range r.Perm(int(size.Int64()))[:n] {
+			offsets = append(offsets, big.NewInt(int64(i)))
+		}
+	} else {
+		seen := make(map[string]bool, n)
+		for len(offsets) < n {
+			offset := new(big.Int).Rand(r, size)
+			if key := offset.String(); !seen[key] {
+				seen[key] = true
+				offsets = append(offsets, offset)
+			}
+		}
+	}
+
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i].Cmp(offsets[j]) < 0
+	})
+	return offsets
+}
@@ -1,6 +1,7 @@
 package crawlrate
 
 import (
+	"math"
 	"time"
 )
 
@@ -8,4 +9,46 @@ type Pulse struct {
 	Volume    int           // Number of connections required for each proxy
 	Frequency time.Duration // Maximum runtime per keyword
 	Duration  time.Duration // Total duration required to process all keywords
+	Volumes   []int         // Number of connections required for each individual proxy, if they differ
+
+	OverrunRate float64       // Expected fraction of jobs still running at the next tick
+	Proxies     int           // Number of proxies required, if calculated by the algorithm
+	TimePeriod  time.Duration // Time period the pulse must finish within, if the algorithm takes one
+}
+
+// volume returns the number of connections required for the proxy at index i.
+func (p *Pulse) volume(i int) int {
+	if i < len(p.Volumes) {
+		return p.Volumes[i]
+	}
+	return p.Volume
+}
+
+// volumes returns the number of connections required for each of the proxies, given
+// their weights, along with the number of connections in a single tick across all of
+// them. The Volume of a proxy of weight 1 is shared between the proxies in proportion
+// to their weights, unless the pulse holds a volume for each individual proxy.
+func (p *Pulse) volumes(weights []float64) ([]int, int) {
+	var volumes []int
+	if p.Volumes != nil || !weighted(weights) {
+		volumes = make([]int, len(weights))
+		for i := range volumes {
+			volumes[i] = p.volume(i)
+		}
+	} else {
+		var totalWeight float64
+		for _, w := range weights {
+			totalWeight += w
+		}
+		// Round up, so the proxies never have less than the Volume of
+		// the weights, allowing for floating point error.
+		total := int(math.Ceil(float64(p.Volume)*totalWeight - 1e-9))
+		volumes = shareVolume(total, weights)
+	}
+
+	var rowCapacity int
+	for _, v := range volumes {
+		rowCapacity += v
+	}
+	return volumes, rowCapacity
 }
@@ -0,0 +1,354 @@
+package crawlrate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PulseInputs holds the inputs to a PulseAlgorithm. Each algorithm only reads the
+// inputs it needs.
+type PulseInputs struct {
+	KeywordCount  int           // Number of keywords to process
+	ProxyCount    int           // Number of proxies available
+	AvgJobRuntime time.Duration // Average job runtime
+	MinimumDelay  time.Duration // Minimum delay between jobs
+
+	TimePeriod      time.Duration // Fixed time period, as used by the duration and proxies algorithms
+	ConnectionCount int           // Maximum connections per proxy, as used by the connections and proxies algorithms
+	Throughput      int           // Keywords per minute, as used by the throughput algorithm
+
+	Capacities []int               // Maximum connections for each proxy, replaces ProxyCount if set
+	Runtimes   RuntimeDistribution // Job runtime distribution, replaces AvgJobRuntime if set
+	Confidence float64             // Fraction of jobs that must finish before the next tick, used with Runtimes
+
+	Cooldown Cooldown      // Rest for each proxy, which the built in algorithms allow for in the duration
+	Jitter   time.Duration // Jitter of the crawl plan, which delays the start of each rest of the cooldown
+	Weights  []float64     // Weight of each proxy relative to a proxy of weight 1, each greater than 0, replaces ProxyCount if set
+
+	SubnetLimit SubnetLimit // Connections through each subnet in a tick, which the built in algorithms allow for in the duration
+	Proxies     []Proxy     // Proxies in order, whose addresses give their subnets, used with SubnetLimit
+}
+
+// rowLimits returns the limits on the rows of a plan across the first proxyCount of
+// the proxies. The proxies algorithm takes as many of the proxies as it needs, so only
+// needs there to be enough of them.
+func (in PulseInputs) rowLimits(proxyCount int) (rowLimits, error) {
+	if err := in.Cooldown.validate(); err != nil {
+		return rowLimits{}, err
+	}
+	if err := in.SubnetLimit.validate(); err != nil {
+		return rowLimits{}, err
+	}
+
+	l := rowLimits{cooldown: in.Cooldown, jitter: in.Jitter}
+	if in.SubnetLimit.Max == 0 {
+		return l, nil
+	}
+	if len(in.Proxies) < proxyCount {
+		return rowLimits{}, &InputError{"proxies", len(in.Proxies), ErrSubnetProxies}
+	}
+	l.subnets = in.SubnetLimit.of(in.Proxies[:proxyCount])
+	return l, nil
+}
+
+// proxyCount returns the number of proxies available.
+func (in PulseInputs) proxyCount() int {
+	switch {
+	case in.Capacities != nil:
+		return len(in.Capacities)
+	case in.Weights != nil:
+		return len(in.Weights)
+	}
+	return in.ProxyCount
+}
+
+// weights returns the weight of each of the proxies available.
+func (in PulseInputs) weights() []float64 {
+	if in.Weights != nil && in.Capacities == nil {
+		return normalise(in.Weights)
+	}
+	return normalise(make([]float64, in.proxyCount()))
+}
+
+// unweighted returns the inputs for the same number of proxies of weight 1 that is
+// equivalent to the weighted proxies. The keywords and throughput are scaled by the
+// number of proxies over their total weight, so each proxy of weight 1 has the same
+// share of the work it has amongst the weighted proxies.
+func (in PulseInputs) unweighted() PulseInputs {
+	ws := in.weights()
+	if !weighted(ws) {
+		return in
+	}
+
+	var totalWeight float64
+	for _, w := range ws {
+		totalWeight += w
+	}
+	scale := float64(len(ws)) / totalWeight
+
+	out := in
+	out.ProxyCount = len(ws)
+	out.Weights = nil
+	out.KeywordCount = int(math.Ceil(float64(in.KeywordCount) * scale))
+	if in.Throughput > 0 {
+		out.Throughput = int(math.Ceil(float64(in.Throughput) * scale))
+	}
+	return out
+}
+
+// fitWeights sets the duration of the pulse to the ticks the weighted proxies take to
+// process the keywords.
+func (in PulseInputs) fitWeights(p *Pulse) {
+	ws := in.weights()
+	if !weighted(ws) || p.Volumes != nil {
+		return
+	}
+
+	_, rowCapacity := p.volumes(ws)
+	if rowCapacity > 0 {
+		rows := (in.KeywordCount + rowCapacity - 1) / rowCapacity
+		p.Duration = time.Duration(rows) * p.Frequency
+	}
+}
+
+// PulseAlgorithm is implemented by any algorithm that can calculate a Pulse.
+type PulseAlgorithm interface {
+	// Pulse calculates a pulse from the given inputs.
+	Pulse(in PulseInputs) (*Pulse, error)
+
+	// Description returns a one line description of the algorithm.
+	Description() string
+}
+
+var (
+	algorithmsMu sync.RWMutex
+	algorithms   = make(map[string]PulseAlgorithm)
+)
+
+// Register makes a PulseAlgorithm available by the provided name. If Register is
+// called twice with the same name or if algorithm is nil, it panics.
+func Register(name string, algorithm PulseAlgorithm) {
+	algorithmsMu.Lock()
+	defer algorithmsMu.Unlock()
+
+	if algorithm == nil {
+		panic("crawlrate: Register algorithm is nil")
+	}
+	if _, dup := algorithms[name]; dup {
+		panic("crawlrate: Register called twice for algorithm " + name)
+	}
+	algorithms[name] = algorithm
+}
+
+// Lookup returns the PulseAlgorithm registered with the provided name.
+func Lookup(name string) (PulseAlgorithm, error) {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+
+	algorithm, ok := algorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+	}
+	return algorithm, nil
+}
+
+// Algorithms returns a sorted list of the names of the registered algorithms.
+func Algorithms() []string {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+
+	names := make([]string, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register("duration", fixedDurationAlgorithm{})
+	Register("connections", fixedConnectionsAlgorithm{})
+	Register("throughput", fixedThroughputAlgorithm{})
+	Register("proxies", minimumProxiesAlgorithm{})
+}
+
+var errCapacitiesAndRuntimes = errors.New("capacities cannot be combined with a runtime distribution")
+
+type fixedDurationAlgorithm struct{}
+
+func (fixedDurationAlgorithm) Description() string {
+	return "fixed time period, calculates the connections required for each proxy"
+}
+
+func (a fixedDurationAlgorithm) Pulse(in PulseInputs) (*Pulse, error) {
+	if err := validateWeights(in.Weights); err != nil {
+		return nil, err
+	}
+
+	p, err := a.pulse(in.unweighted())
+	if err != nil {
+		return nil, err
+	}
+
+	// Resting proxies, or proxies held back by the limit of their subnet, need
+	// more connections to finish within the time period, but more than the
+	// cooldown allows between rests or the subnet allows in a tick gains nothing.
+	maxVolume := p.Volume
+	if in.Cooldown.Requests > maxVolume {
+		maxVolume = in.Cooldown.Requests
+	}
+	if in.SubnetLimit.Max > maxVolume {
+		maxVolume = in.SubnetLimit.Max
+	}
+	l, err := in.rowLimits(in.proxyCount())
+	if err != nil {
+		return nil, err
+	}
+	if err := l.fit(p, in.KeywordCount, in.weights(), in.TimePeriod, maxVolume); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (fixedDurationAlgorithm) pulse(in PulseInputs) (*Pulse, error) {
+	switch {
+	case in.Capacities != nil && in.Runtimes != nil:
+		return nil, errCapacitiesAndRuntimes
+	case in.Capacities != nil:
+		return FixedDurationCapacities(in.KeywordCount, in.Capacities, in.AvgJobRuntime, in.MinimumDelay, in.TimePeriod)
+	case in.Runtimes != nil:
+		return FixedDurationDistribution(in.KeywordCount, in.ProxyCount, in.Runtimes, in.Confidence, in.MinimumDelay, in.TimePeriod)
+	}
+	return FixedDuration(in.KeywordCount, in.ProxyCount, in.AvgJobRuntime, in.MinimumDelay, in.TimePeriod)
+}
+
+type fixedConnectionsAlgorithm struct{}
+
+func (fixedConnectionsAlgorithm) Description() string {
+	return "fixed connections per proxy, calculates the duration required"
+}
+
+func (a fixedConnectionsAlgorithm) Pulse(in PulseInputs) (*Pulse, error) {
+	if err := validateWeights(in.Weights); err != nil {
+		return nil, err
+	}
+
+	p, err := a.pulse(in.unweighted())
+	if err != nil {
+		return nil, err
+	}
+	in.fitWeights(p)
+	l, err := in.rowLimits(in.proxyCount())
+	if err != nil {
+		return nil, err
+	}
+	if err := l.fit(p, in.KeywordCount, in.weights(), 0, p.Volume); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (fixedConnectionsAlgorithm) pulse(in PulseInputs) (*Pulse, error) {
+	switch {
+	case in.Capacities != nil && in.Runtimes != nil:
+		return nil, errCapacitiesAndRuntimes
+	case in.Capacities != nil:
+		return FixedConnectionsCapacities(in.KeywordCount, in.Capacities, in.AvgJobRuntime, in.MinimumDelay)
+	case in.Runtimes != nil:
+		return FixedConnectionsDistribution(in.KeywordCount, in.ProxyCount, in.Runtimes, in.Confidence, in.MinimumDelay, in.ConnectionCount)
+	}
+	return FixedConnections(in.KeywordCount, in.ProxyCount, in.AvgJobRuntime, in.MinimumDelay, in.ConnectionCount)
+}
+
+type fixedThroughputAlgorithm struct{}
+
+func (fixedThroughputAlgorithm) Description() string {
+	return "fixed keywords per minute, calculates the connections and duration required"
+}
+
+func (fixedThroughputAlgorithm) Pulse(in PulseInputs) (*Pulse, error) {
+	if err := validateWeights(in.Weights); err != nil {
+		return nil, err
+	}
+
+	u := in.unweighted()
+	p, err := FixedThroughput(u.KeywordCount, u.ProxyCount, u.AvgJobRuntime, u.MinimumDelay, u.Throughput)
+	if err != nil {
+		return nil, err
+	}
+	in.fitWeights(p)
+
+	// The throughput is held between rests, so the rests lengthen the duration.
+	l, err := in.rowLimits(in.proxyCount())
+	if err != nil {
+		return nil, err
+	}
+	if err := l.fit(p, in.KeywordCount, in.weights(), 0, p.Volume); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type minimumProxiesAlgorithm struct{}
+
+func (minimumProxiesAlgorithm) Description() string {
+	return "fixed time period and connections per proxy, calculates the proxies required"
+}
+
+func (minimumProxiesAlgorithm) Pulse(in PulseInputs) (*Pulse, error) {
+	if err := in.Cooldown.validate(); err != nil {
+		return nil, err
+	}
+	if err := in.SubnetLimit.validate(); err != nil {
+		return nil, err
+	}
+
+	n, p, err := MinimumProxies(in.KeywordCount, in.AvgJobRuntime, in.MinimumDelay, in.ConnectionCount, in.TimePeriod)
+	if err != nil || (in.Cooldown.Requests == 0 && in.SubnetLimit.Max == 0) {
+		return p, err
+	}
+
+	// Resting proxies, or proxies held back by the limit of their subnet, may
+	// need more proxies alongside them to finish within the time period. Search
+	// for the fewest that do, up to one proxy for every keyword, taking the
+	// proxies in order when they are limited by subnet.
+	fits := func(n int) (*Pulse, error) {
+		p, err := FixedDuration(in.KeywordCount, n, in.AvgJobRuntime, in.MinimumDelay, in.TimePeriod)
+		if err != nil {
+			return nil, err
+		}
+		l, err := in.rowLimits(n)
+		if err != nil {
+			return nil, err
+		}
+		if err := l.fit(p, in.KeywordCount, normalise(make([]float64, n)), in.TimePeriod, in.ConnectionCount); err != nil {
+			return nil, err
+		}
+		p.Proxies = n
+		return p, nil
+	}
+
+	lo, hi := n, in.KeywordCount
+	if in.SubnetLimit.Max > 0 && hi > len(in.Proxies) {
+		hi = len(in.Proxies)
+	}
+	if hi < lo {
+		hi = lo
+	}
+	if p, err = fits(hi); err != nil {
+		return nil, err
+	}
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if q, err := fits(mid); err == nil {
+			p, hi = q, mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return p, nil
+}
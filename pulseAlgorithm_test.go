@@ -0,0 +1,111 @@
+package crawlrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var algorithmTests = []struct {
+	name string
+	in   PulseInputs
+	p    *Pulse
+}{
+	{"duration", PulseInputs{KeywordCount: 100, ProxyCount: 10, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, TimePeriod: time.Duration(120) * time.Second},
+		&Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{"duration", PulseInputs{KeywordCount: 12, Capacities: []int{4, 2}, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, TimePeriod: time.Duration(180) * time.Second},
+		&Pulse{Volume: 3, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second, Volumes: []int{3, 1}}},
+	{"connections", PulseInputs{KeywordCount: 100, ProxyCount: 10, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, ConnectionCount: 5},
+		&Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{"connections", PulseInputs{KeywordCount: 10, ProxyCount: 5, Runtimes: histogram, Confidence: 0.95, MinimumDelay: time.Duration(15) * time.Second, ConnectionCount: 2},
+		&Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second}},
+	{"throughput", PulseInputs{KeywordCount: 30, ProxyCount: 5, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, Throughput: 10},
+		&Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(180) * time.Second}},
+	{"proxies", PulseInputs{KeywordCount: 100, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, ConnectionCount: 5, TimePeriod: time.Duration(120) * time.Second},
+		&Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second, Proxies: 10}},
+
+	// Proxies weighted 2:1:1 do the work of 4 proxies of weight 1
+	{"duration", PulseInputs{KeywordCount: 100, Weights: []float64{2, 1, 1}, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, TimePeriod: time.Duration(120) * time.Second},
+		&Pulse{Volume: 13, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}},
+	{"connections", PulseInputs{KeywordCount: 100, Weights: []float64{2, 1, 1}, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, ConnectionCount: 5},
+		&Pulse{Volume: 5, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(300) * time.Second}},
+	{"throughput", PulseInputs{KeywordCount: 40, Weights: []float64{2, 1, 1}, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, Throughput: 8},
+		&Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(300) * time.Second}},
+}
+
+func Test_PulseAlgorithms(t *testing.T) {
+	for k, tt := range algorithmTests {
+		algorithm, err := Lookup(tt.name)
+		if err != nil {
+			t.Errorf("Error test: %d Error: %s", k, err)
+			continue
+		}
+		p, err := algorithm.Pulse(tt.in)
+		if err != nil {
+			t.Errorf("Error test: %d Error: %s", k, err)
+			continue
+		}
+		comparePulse(t, k, p, tt.p)
+	}
+}
+
+func Test_PulseAlgorithmsWeights(t *testing.T) {
+	// A weight given in the inputs is never taken as unset, so must be greater than 0
+	for _, name := range []string{"duration", "connections", "throughput"} {
+		algorithm, _ := Lookup(name)
+		in := PulseInputs{KeywordCount: 40, Weights: []float64{1, 0}, AvgJobRuntime: minute, TimePeriod: time.Hour, ConnectionCount: 1, Throughput: 1}
+		if _, err := algorithm.Pulse(in); !errors.Is(err, ErrInvalidWeight) {
+			t.Errorf("%s: Expected %v. Got: %v\n", name, ErrInvalidWeight, err)
+		}
+	}
+}
+
+type constantAlgorithm struct{}
+
+func (constantAlgorithm) Description() string { return "always one connection per minute" }
+
+func (constantAlgorithm) Pulse(in PulseInputs) (*Pulse, error) {
+	return &Pulse{Volume: 1, Frequency: time.Minute, Duration: time.Duration(in.KeywordCount) * time.Minute}, nil
+}
+
+func Test_Register(t *testing.T) {
+	Register("constant", constantAlgorithm{})
+	defer func() {
+		algorithmsMu.Lock()
+		delete(algorithms, "constant")
+		algorithmsMu.Unlock()
+	}()
+
+	names := Algorithms()
+	exp := []string{"connections", "constant", "duration", "proxies", "throughput"}
+	if len(names) != len(exp) {
+		t.Fatalf("Algorithms exp: %v got: %v", exp, names)
+	}
+	for i := range exp {
+		if names[i] != exp[i] {
+			t.Fatalf("Algorithms exp: %v got: %v", exp, names)
+		}
+	}
+
+	algorithm, err := Lookup("constant")
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	p, _ := algorithm.Pulse(PulseInputs{KeywordCount: 3})
+	if p.Duration != 3*time.Minute {
+		t.Errorf("Duration exp: %s got: %s", 3*time.Minute, p.Duration)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected duplicate Register to panic")
+		}
+	}()
+	Register("constant", constantAlgorithm{})
+}
+
+func Test_LookupUnknown(t *testing.T) {
+	if _, err := Lookup("unknown"); err == nil {
+		t.Errorf("Expected error")
+	}
+}
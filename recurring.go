@@ -0,0 +1,130 @@
+package crawlrate
+
+import (
+	"sort"
+	"time"
+)
+
+// occurrence is a single crawl of a keyword that recurs over a horizon. It may run in
+// any tick from its release until the keyword's next occurrence is released.
+type occurrence struct {
+	keyword          int
+	release, expires time.Duration
+}
+
+// NewRecurring creates a crawl plan that covers every crawl of the keywords over the
+// horizon, crawling each keyword once every Keyword.Interval. Keywords without an
+// interval are crawled once. Every tick of the horizon holds no more than the pulse
+// has connections for.
+//
+// Each crawl runs in the earliest tick with a free connection once it is due, oldest
+// first and then in order of priority. A crawl that has not run by the time the
+// keyword is next due is superseded by that crawl and is listed, along with any still
+// waiting at the end of the horizon, in an *UnscheduledError returned with the plan.
+// The weighting of the plan does not apply, as the keywords' intervals determine
+// which ticks they run in.
+func NewRecurring(keywords []Keyword, proxies []Proxy, pulse *Pulse, horizon time.Duration, opts ...Option) (cr CrawlPlan, err error) {
+	if pulse.Frequency <= 0 {
+		return nil, &InputError{"pulse.Frequency", pulse.Frequency, ErrInvalidPulse}
+	}
+	if horizon <= 0 {
+		return nil, &InputError{"horizon", horizon, ErrInvalidHorizon}
+	}
+
+	o := newOptions(opts)
+	if err := o.cooldown.validate(); err != nil {
+		return nil, err
+	}
+	if err := o.subnets.validate(); err != nil {
+		return nil, err
+	}
+	ws := weights(proxies)
+	if err := validateWeights(ws); err != nil {
+		return nil, err
+	}
+
+	ticks := int((horizon + pulse.Frequency - 1) / pulse.Frequency)
+	volumes, _ := pulse.volumes(ws)
+	limiter := o.rowLimits(proxies).limiter(volumes, pulse.Frequency)
+	jitter := offsets(o.jitter, o.seed, pulse.Frequency, volumes)
+	assign := o.assigner(proxies)
+
+	keywords = byPriority(ExpandTargets(keywords, o.targets))
+
+	// Every crawl required over the horizon, ordered by when they are due. The
+	// keywords are already in order of priority, so crawls due at the same time
+	// stay in that order.
+	var occurrences []occurrence
+	for i, k := range keywords {
+		if k.Interval < 0 {
+			return nil, &InputError{"keyword.Interval", k.Interval, ErrInvalidInterval}
+		}
+		if k.Interval == 0 {
+			occurrences = append(occurrences, occurrence{i, 0, horizon})
+			continue
+		}
+		for release := time.Duration(0); release < horizon; release += k.Interval {
+			occurrences = append(occurrences, occurrence{i, release, release + k.Interval})
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].release < occurrences[j].release
+	})
+
+	var pending []occurrence
+	var unscheduled []Keyword
+	for tick := 0; tick < ticks; tick++ {
+		t := time.Duration(tick) * pulse.Frequency
+
+		for len(occurrences) > 0 && occurrences[0].release <= t {
+			pending = append(pending, occurrences[0])
+			occurrences = occurrences[1:]
+		}
+
+		// Drop the crawls superseded by the keyword's next crawl.
+		waiting := pending[:0]
+		for _, oc := range pending {
+			if oc.expires <= t {
+				unscheduled = append(unscheduled, keywords[oc.keyword])
+				continue
+			}
+			waiting = append(waiting, oc)
+		}
+		pending = waiting
+
+		// Proxies resting in the tick, or held back by the limit of their
+		// subnet, leave it with fewer connections.
+		row := limiter.next()
+		var rowCapacity int
+		for _, v := range row {
+			rowCapacity += v
+		}
+
+		due := pending
+		if len(due) > rowCapacity {
+			due = due[:rowCapacity]
+		}
+		dueKeywords := make([]Keyword, len(due))
+		for i, oc := range due {
+			dueKeywords[i] = keywords[oc.keyword]
+		}
+
+		// A tick always has room for the crawls due in it.
+		placements, _ := assign(dueKeywords, []int{len(due)}, [][]int{row}, o.lastRow)
+		for _, p := range placements {
+			cr = append(cr, CrawlRule{t + jitter[p.proxy][p.conn], proxies[p.proxy], p.conn, dueKeywords[p.keyword].Text, dueKeywords[p.keyword].Target})
+		}
+		pending = pending[len(due):]
+	}
+
+	OrderedBy(o.ordering...).Sort(cr)
+
+	// Crawls still waiting at the end of the horizon are missed.
+	for _, oc := range append(pending, occurrences...) {
+		unscheduled = append(unscheduled, keywords[oc.keyword])
+	}
+	if len(unscheduled) > 0 {
+		return cr, &UnscheduledError{Keywords: unscheduled}
+	}
+	return cr, nil
+}
@@ -0,0 +1,139 @@
+package crawlrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var recurringTests = []struct {
+	keywords    []Keyword
+	pulse       *Pulse
+	horizon     time.Duration
+	out         CrawlPlan
+	unscheduled []string
+}{
+	{
+		[]Keyword{
+			{Text: "hourly", Interval: time.Duration(3600) * time.Second},
+			{Text: "once"},
+		},
+		&Pulse{Volume: 1, Frequency: time.Duration(1800) * time.Second},
+		time.Duration(3) * time.Hour,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "hourly", Target{}},
+			{time.Duration(1800) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "once", Target{}},
+			{time.Duration(3600) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "hourly", Target{}},
+			{time.Duration(7200) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "hourly", Target{}},
+		},
+		nil,
+	},
+	{
+		[]Keyword{
+			{Text: "a", Interval: time.Duration(3600) * time.Second},
+			{Text: "b", Interval: time.Duration(1800) * time.Second},
+		},
+		&Pulse{Volume: 2, Frequency: time.Duration(1800) * time.Second},
+		time.Duration(1) * time.Hour,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "a", Target{}},
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "b", Target{}},
+			{time.Duration(1800) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "b", Target{}},
+		},
+		nil,
+	},
+
+	// Crawls superseded before they run are unscheduled
+	{
+		[]Keyword{
+			{Text: "a", Interval: time.Duration(3600) * time.Second},
+			{Text: "b", Interval: time.Duration(3600) * time.Second},
+		},
+		&Pulse{Volume: 1, Frequency: time.Duration(3600) * time.Second},
+		time.Duration(2) * time.Hour,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "a", Target{}},
+			{time.Duration(3600) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "a", Target{}},
+		},
+		[]string{"b", "b"},
+	},
+
+	// Higher priority keywords take the capacity
+	{
+		[]Keyword{
+			{Text: "a", Interval: time.Duration(3600) * time.Second},
+			{Text: "b", Priority: 1, Interval: time.Duration(3600) * time.Second},
+		},
+		&Pulse{Volume: 1, Frequency: time.Duration(3600) * time.Second},
+		time.Duration(2) * time.Hour,
+		CrawlPlan{
+			{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "b", Target{}},
+			{time.Duration(3600) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "b", Target{}},
+		},
+		[]string{"a", "a"},
+	},
+}
+
+func Test_Recurring(t *testing.T) {
+	for k, tt := range recurringTests {
+		cp, err := NewRecurring(tt.keywords, generateProxies("127.0.0.", 1), tt.pulse, tt.horizon)
+		comparePlan(t, k, cp, tt.out)
+
+		var unscheduled *UnscheduledError
+		if errors.As(err, &unscheduled) != (tt.unscheduled != nil) {
+			t.Errorf("Test %d: Error: %v\n", k, err)
+			continue
+		}
+		if unscheduled == nil {
+			continue
+		}
+		if len(unscheduled.Keywords) != len(tt.unscheduled) {
+			t.Errorf("Test %d: Unscheduled keywords wrong. Got: %v Expected: %v\n", k, unscheduled.Keywords, tt.unscheduled)
+			continue
+		}
+		for n, kw := range unscheduled.Keywords {
+			if kw.Text != tt.unscheduled[n] {
+				t.Errorf("Test %d: Unscheduled keywords wrong. Got: %v Expected: %v\n", k, unscheduled.Keywords, tt.unscheduled)
+			}
+		}
+	}
+}
+
+func Test_RecurringVolume(t *testing.T) {
+	// 10 hourly keywords over a day across 2 proxies with 3 connections
+	keywords := generateKeywords("keyword-", 10)
+	for i := range keywords {
+		keywords[i].Interval = time.Duration(3600) * time.Second
+	}
+	p := &Pulse{Volume: 3, Frequency: time.Duration(600) * time.Second}
+
+	cp, err := NewRecurring(keywords, generateProxies("127.0.0.", 2), p, time.Duration(24)*time.Hour)
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+	if len(cp) != 240 {
+		t.Errorf("Expected %d rules. Got: %d\n", 240, len(cp))
+	}
+
+	perTick := make(map[time.Duration]int)
+	for _, r := range cp {
+		perTick[r.Time]++
+	}
+	for tick, n := range perTick {
+		if n > 6 {
+			t.Errorf("Error test: %s holds %d rules, more than the 6 connections\n", tick, n)
+		}
+	}
+}
+
+func Test_RecurringErrors(t *testing.T) {
+	p := &Pulse{Volume: 1, Frequency: time.Duration(60) * time.Second}
+	if _, err := NewRecurring(generateKeywords("keyword-", 1), generateProxies("127.0.0.", 1), p, 0); !errors.Is(err, ErrInvalidHorizon) {
+		t.Errorf("Expected %v. Got: %v\n", ErrInvalidHorizon, err)
+	}
+
+	keywords := []Keyword{{Text: "keyword-0", Interval: -time.Second}}
+	if _, err := NewRecurring(keywords, generateProxies("127.0.0.", 1), p, time.Hour); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("Expected %v. Got: %v\n", ErrInvalidInterval, err)
+	}
+}
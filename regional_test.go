@@ -0,0 +1,164 @@
+package crawlrate
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_ParseProxyGroups(t *testing.T) {
+	groups, err := ParseProxyGroups([]string{"uk\t10.0.0.1", "", "us\t10.0.1.1", "uk\t10.0.0.2:3128", "10.0.2.1", " \t", ""})
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+
+	out := []ProxyGroup{
+		{"uk", []Proxy{{Host: "10.0.0.1"}, {Host: "10.0.0.2", Port: 3128}}},
+		{"us", []Proxy{{Host: "10.0.1.1"}}},
+		{"", []Proxy{{Host: "10.0.2.1"}}},
+	}
+	if len(groups) != len(out) {
+		t.Fatalf("Non-equal slice lengths. Got: %d Expected: %d\n", len(groups), len(out))
+	}
+	for k := range out {
+		if groups[k].Region != out[k].Region || len(groups[k].Proxies) != len(out[k].Proxies) {
+			t.Errorf("Error test: %d exp: %v got: %v\n", k, out[k], groups[k])
+			continue
+		}
+		for n := range out[k].Proxies {
+			if groups[k].Proxies[n] != out[k].Proxies[n] {
+				t.Errorf("Error test: %d exp: %v got: %v\n", k, out[k], groups[k])
+			}
+		}
+	}
+
+	if _, err := ParseProxyGroups([]string{"uk\t10.0.0.1:port"}); !errors.Is(err, ErrInvalidProxy) {
+		t.Errorf("Expected %v. Got: %v\n", ErrInvalidProxy, err)
+	}
+}
+
+func Test_ParseProxyGroupsWeights(t *testing.T) {
+	groups, err := ParseProxyGroups([]string{"uk\t10.0.0.1\t2", "10.0.2.1\t0.5", "us\t10.0.1.1"})
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+
+	out := []Proxy{{Host: "10.0.0.1", Weight: 2}, {Host: "10.0.2.1", Weight: 0.5}, {Host: "10.0.1.1"}}
+	regions := []string{"uk", "", "us"}
+	if len(groups) != len(out) {
+		t.Fatalf("Non-equal slice lengths. Got: %d Expected: %d\n", len(groups), len(out))
+	}
+	for k := range out {
+		if groups[k].Region != regions[k] || len(groups[k].Proxies) != 1 || groups[k].Proxies[0] != out[k] {
+			t.Errorf("Error test: %d exp: %s %v got: %v\n", k, regions[k], out[k], groups[k])
+		}
+	}
+
+	for _, line := range []string{"uk\t10.0.0.1\theavy", "10.0.0.1\t-1", "10.0.0.1\t0", "uk\t10.0.0.1\t0"} {
+		if _, err := ParseProxyGroups([]string{line}); !errors.Is(err, ErrInvalidWeight) {
+			t.Errorf("%q: Expected %v. Got: %v\n", line, ErrInvalidWeight, err)
+		}
+	}
+}
+
+func Test_Regional(t *testing.T) {
+	keywords := []Keyword{
+		{Text: "uk-0", Region: "uk"},
+		{Text: "us-0", Region: "us"},
+		{Text: "uk-1", Region: "uk"},
+		{Text: "fr-0", Region: "fr"},
+		{Text: "uk-2", Region: "uk"},
+		{Text: "us-1", Region: "us"},
+		{Text: "uk-3", Region: "uk"},
+	}
+	groups := []ProxyGroup{
+		{"uk", generateProxies("10.0.0.", 2)},
+		{"us", generateProxies("10.0.1.", 1)},
+	}
+	algorithm, _ := Lookup("connections")
+	in := PulseInputs{AvgJobRuntime: minute, ConnectionCount: 1}
+
+	cp, pulses, err := NewRegional(keywords, groups, algorithm, in)
+
+	// Each keyword is crawled through a proxy of its region
+	region := map[string]string{"10.0.0.0": "uk", "10.0.0.1": "uk", "10.0.1.0": "us"}
+	if len(cp) != 6 {
+		t.Errorf("Expected %d rules. Got: %d\n", 6, len(cp))
+	}
+	for k, r := range cp {
+		if r.Keyword[:2] != region[r.Proxy.String()] {
+			t.Errorf("Error test: %d %s crawled through %s\n", k, r.Keyword, r.Proxy)
+		}
+	}
+
+	// With a pulse for each group
+	comparePulse(t, 0, pulses[0], &Pulse{Volume: 1, Frequency: minute, Duration: 2 * minute})
+	comparePulse(t, 1, pulses[1], &Pulse{Volume: 1, Frequency: minute, Duration: 2 * minute})
+
+	// Keywords without a group for their region are unscheduled
+	var unscheduled *UnscheduledError
+	if !errors.As(err, &unscheduled) || len(unscheduled.Keywords) != 1 || unscheduled.Keywords[0].Text != "fr-0" {
+		t.Errorf("Expected fr-0 unscheduled. Got: %v\n", err)
+	}
+}
+
+func Test_RegionalWeights(t *testing.T) {
+	var keywords []Keyword
+	for _, k := range generateKeywords("us-", 80) {
+		keywords = append(keywords, Keyword{Text: k.Text, Region: "us"})
+	}
+	keywords = append(keywords, Keyword{Text: "uk-0", Region: "uk"}, Keyword{Text: "uk-1", Region: "uk"})
+
+	uk := generateProxies("10.0.0.", 2)
+	uk[0].Weight, uk[1].Weight = 2, 2
+	groups := []ProxyGroup{{"uk", uk}, {"us", generateProxies("10.0.1.", 4)}}
+
+	// The weights of every proxy, as the crawlplan command passes them, do not
+	// carry over to the unweighted group
+	algorithm, _ := Lookup("duration")
+	in := PulseInputs{AvgJobRuntime: minute, TimePeriod: 10 * minute, Weights: []float64{2, 2, 1, 1, 1, 1}}
+	cp, pulses, err := NewRegional(keywords, groups, algorithm, in)
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+	if len(cp) != len(keywords) {
+		t.Errorf("Expected %d rules. Got: %d\n", len(keywords), len(cp))
+	}
+	comparePulse(t, 0, pulses[0], &Pulse{Volume: 1, Frequency: minute, Duration: 10 * minute})
+	comparePulse(t, 1, pulses[1], &Pulse{Volume: 2, Frequency: minute, Duration: 10 * minute})
+}
+
+func Test_RegionalErrors(t *testing.T) {
+	algorithm, _ := Lookup("connections")
+	in := PulseInputs{AvgJobRuntime: minute, ConnectionCount: 1}
+	keywords := []Keyword{{Text: "uk-0", Region: "uk"}}
+
+	groups := []ProxyGroup{{"uk", generateProxies("10.0.0.", 1)}, {"uk", generateProxies("10.0.1.", 1)}}
+	if _, _, err := NewRegional(keywords, groups, algorithm, in); !errors.Is(err, ErrDuplicateRegion) {
+		t.Errorf("Expected %v. Got: %v\n", ErrDuplicateRegion, err)
+	}
+
+	// Errors calculating a group's pulse name the region
+	groups = []ProxyGroup{{"uk", nil}}
+	_, _, err := NewRegional(keywords, groups, algorithm, in)
+	var regionErr *RegionError
+	if !errors.Is(err, ErrNoProxies) || !errors.As(err, &regionErr) || regionErr.Region != "uk" {
+		t.Errorf("Expected %v for region uk. Got: %v\n", ErrNoProxies, err)
+	}
+
+	// A subnet limit is held within each group, so a subnet cannot span two
+	groups = []ProxyGroup{{"uk", []Proxy{{Host: "10.0.0.1"}}}, {"us", []Proxy{{Host: "10.0.0.2"}}}}
+	limit := WithSubnetLimit(SubnetLimit{24, 48, 1})
+	if _, _, err := NewRegional(keywords, groups, algorithm, in, limit); !errors.Is(err, ErrSharedSubnet) {
+		t.Errorf("Expected %v. Got: %v\n", ErrSharedSubnet, err)
+	}
+	groups[1].Proxies[0].Host = "10.0.1.2"
+	if _, _, err := NewRegional(keywords, groups, algorithm, in, limit); err != nil {
+		t.Errorf("Error: %s\n", err)
+	}
+
+	groups = []ProxyGroup{{"uk", generateProxies("10.0.0.", 2)}}
+	in.Capacities = []int{1}
+	if _, _, err := NewRegional(keywords, groups, algorithm, in); !errors.Is(err, ErrInvalidCapacity) {
+		t.Errorf("Expected %v. Got: %v\n", ErrInvalidCapacity, err)
+	}
+}
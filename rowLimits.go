@@ -0,0 +1,126 @@
+package crawlrate
+
+import (
+	"time"
+)
+
+// rowLimits holds back the connections available to the proxies in each row of a
+// plan, for the rests of the cooldown and the limit of each subnet.
+type rowLimits struct {
+	cooldown Cooldown
+	subnets  subnets
+	jitter   time.Duration // Most a job may start after its tick, delaying the rest that follows
+}
+
+// rowLimits returns the limits on the rows of a plan across the proxies.
+func (o *options) rowLimits(proxies []Proxy) rowLimits {
+	return rowLimits{o.cooldown, o.subnets.of(proxies), o.jitter}
+}
+
+// none reports whether the rows are left with every connection of the proxies.
+func (l rowLimits) none() bool {
+	return l.cooldown.Requests == 0 && l.subnets.Max == 0
+}
+
+// rowLimiter works out the connections available to each proxy a row at a time. A
+// proxy is taken to serve every connection available to it, so a plan that uses
+// fewer rests no later than it needs to and never serves more than the cooldown
+// allows between rests.
+type rowLimiter struct {
+	rowLimits
+	volumes   []int
+	frequency time.Duration
+	resting   *resting
+	row       int
+}
+
+func (l rowLimits) limiter(volumes []int, frequency time.Duration) *rowLimiter {
+	return &rowLimiter{
+		rowLimits: l,
+		volumes:   volumes,
+		frequency: frequency,
+		resting:   l.cooldown.resting(len(volumes)),
+	}
+}
+
+// next returns the number of connections available to each proxy in the next row.
+// Only the connections the subnet limit leaves count towards the rests.
+func (r *rowLimiter) next() []int {
+	tick := r.row
+	t := time.Duration(tick) * r.frequency
+	r.row++
+
+	row := r.subnets.limit(r.resting.available(r.volumes, t), tick)
+
+	// Rest once the jobs of the tick have finished, which a job offset by the
+	// jitter may not do until that long after the end of the tick.
+	jitter := r.jitter
+	if jitter > r.frequency {
+		jitter = r.frequency
+	}
+	r.resting.serve(row, t+r.frequency+jitter)
+	return row
+}
+
+// rowVolumes returns the number of connections available to each proxy in each of the
+// rows, given the number of connections each proxy is allowed.
+func (l rowLimits) rowVolumes(volumes []int, rows int, frequency time.Duration) [][]int {
+	r := l.limiter(volumes, frequency)
+	grid := make([][]int, rows)
+	for row := range grid {
+		grid[row] = r.next()
+	}
+	return grid
+}
+
+// rows returns the number of rows it takes to serve the keywords, or -1 if the proxies
+// have no connections.
+func (l rowLimits) rows(keywordCount int, volumes []int, frequency time.Duration) int {
+	var total int
+	for _, v := range volumes {
+		total += v
+	}
+	if total == 0 {
+		return -1
+	}
+
+	r := l.limiter(volumes, frequency)
+	var rows int
+	for served := 0; served < keywordCount; rows++ {
+		for _, v := range r.next() {
+			served += v
+		}
+	}
+	return rows
+}
+
+// fit extends the duration of the pulse, for proxies of the given weights, to allow
+// for the connections held back from its rows. If the pulse must finish within
+// timePeriod, its volume is raised, up to maxVolume, to make up for them.
+func (l rowLimits) fit(p *Pulse, keywordCount int, weights []float64, timePeriod time.Duration, maxVolume int) error {
+	if l.none() {
+		return nil
+	}
+
+	for {
+		volumes, _ := p.volumes(weights)
+		rows := l.rows(keywordCount, volumes, p.Frequency)
+		if rows < 0 {
+			return ErrInfeasibleWindow
+		}
+
+		d := time.Duration(rows) * p.Frequency
+		if timePeriod == 0 || d <= timePeriod {
+			if d > p.Duration {
+				p.Duration = d
+			}
+			return nil
+		}
+
+		// Per proxy volumes are fixed by their capacities.
+		if p.Volumes != nil || p.Volume >= maxVolume {
+			return ErrInfeasibleWindow
+		}
+		p.Volume++
+	}
+}
@@ -0,0 +1,167 @@
+package crawlrate
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// RuntimeDistribution describes how long jobs take to run. It can be used
+// in place of a single average job runtime when runtimes vary widely.
+type RuntimeDistribution interface {
+	// Quantile returns the runtime within which the given fraction of jobs finish.
+	Quantile(confidence float64) time.Duration
+
+	// Overrun returns the fraction of jobs that take longer than runtime.
+	Overrun(runtime time.Duration) float64
+}
+
+// RuntimeSamples is a RuntimeDistribution built from a list of observed job runtimes.
+type RuntimeSamples []time.Duration
+
+// Quantile uses the nearest-rank method, so the runtime returned is always one of
+// the samples.
+func (rs RuntimeSamples) Quantile(confidence float64) time.Duration {
+	if len(rs) == 0 {
+		return 0
+	}
+
+	sorted := make(RuntimeSamples, len(rs))
+	copy(sorted, rs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	rank := int(math.Ceil(confidence*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func (rs RuntimeSamples) Overrun(runtime time.Duration) float64 {
+	if len(rs) == 0 {
+		return 0
+	}
+
+	var over int
+	for _, r := range rs {
+		if r > runtime {
+			over++
+		}
+	}
+	return float64(over) / float64(len(rs))
+}
+
+// RuntimeBucket is a single bucket of a RuntimeHistogram.
+type RuntimeBucket struct {
+	Runtime time.Duration // Longest runtime counted in the bucket
+	Count   int           // Number of jobs counted in the bucket
+}
+
+// RuntimeHistogram is a RuntimeDistribution built from bucketed job runtimes. Jobs
+// are assumed to take as long as the upper bound of their bucket, so the figures
+// reported are pessimistic.
+type RuntimeHistogram []RuntimeBucket
+
+func (rh RuntimeHistogram) Quantile(confidence float64) time.Duration {
+	sorted := rh.sorted()
+
+	var total int
+	for _, b := range sorted {
+		total += b.Count
+	}
+
+	rank := int(math.Ceil(confidence * float64(total)))
+	var seen int
+	for _, b := range sorted {
+		seen += b.Count
+		if seen >= rank && b.Count > 0 {
+			return b.Runtime
+		}
+	}
+	return 0
+}
+
+func (rh RuntimeHistogram) Overrun(runtime time.Duration) float64 {
+	var total, over int
+	for _, b := range rh {
+		total += b.Count
+		if b.Runtime > runtime {
+			over += b.Count
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return float64(over) / float64(total)
+}
+
+func (rh RuntimeHistogram) sorted() RuntimeHistogram {
+	sorted := make(RuntimeHistogram, len(rh))
+	copy(sorted, rh)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Runtime < sorted[j].Runtime
+	})
+	return sorted
+}
+
+// FixedDurationDistribution algorithm is the FixedDuration algorithm sized so that the
+// given fraction (confidence) of jobs, drawn from the runtime distribution, finish
+// before the next tick. The returned pulse reports the expected overrun rate.
+func FixedDurationDistribution(keywordCount, proxyCount int, runtimes RuntimeDistribution, confidence float64, minimumDelay, timePeriod time.Duration) (*Pulse, error) {
+	runtime, err := quantile(runtimes, confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := FixedDuration(keywordCount, proxyCount, runtime, minimumDelay, timePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	p.OverrunRate = overrunRate(p, runtimes, minimumDelay)
+	return p, nil
+}
+
+// FixedConnectionsDistribution algorithm is the FixedConnections algorithm sized so that
+// the given fraction (confidence) of jobs, drawn from the runtime distribution, finish
+// before the next tick. The returned pulse reports the expected overrun rate.
+func FixedConnectionsDistribution(keywordCount, proxyCount int, runtimes RuntimeDistribution, confidence float64, minimumDelay time.Duration, connectionCount int) (*Pulse, error) {
+	runtime, err := quantile(runtimes, confidence)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := FixedConnections(keywordCount, proxyCount, runtime, minimumDelay, connectionCount)
+	if err != nil {
+		return nil, err
+	}
+
+	p.OverrunRate = overrunRate(p, runtimes, minimumDelay)
+	return p, nil
+}
+
+// quantile returns the runtime within which the given fraction (confidence) of jobs
+// drawn from the runtime distribution finish.
+func quantile(runtimes RuntimeDistribution, confidence float64) (time.Duration, error) {
+	if runtimes == nil {
+		return 0, &InputError{"runtimes", runtimes, ErrInvalidRuntime}
+	}
+	if confidence <= 0 || confidence > 1 {
+		return 0, &InputError{"confidence", confidence, ErrInvalidConfidence}
+	}
+
+	runtime := runtimes.Quantile(confidence)
+	if runtime <= 0 {
+		return 0, &InputError{"runtimes", runtimes, ErrInvalidRuntime}
+	}
+	return runtime, nil
+}
+
+// overrunRate returns the fraction of jobs that will still be running, or within
+// their minimum delay, when the next tick starts.
+func overrunRate(p *Pulse, runtimes RuntimeDistribution, minimumDelay time.Duration) float64 {
+	return runtimes.Overrun(p.Frequency - minimumDelay)
+}
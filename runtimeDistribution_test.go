@@ -0,0 +1,90 @@
+package crawlrate
+
+import (
+	"testing"
+	"time"
+)
+
+// 19 jobs take 45s, 1 long tail job takes 300s
+var longTail = RuntimeSamples{
+	time.Duration(45) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second,
+	time.Duration(45) * time.Second, time.Duration(300) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second,
+	time.Duration(45) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second,
+	time.Duration(45) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second,
+	time.Duration(45) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second, time.Duration(45) * time.Second,
+}
+
+var histogram = RuntimeHistogram{
+	{time.Duration(120) * time.Second, 5},
+	{time.Duration(30) * time.Second, 50},
+	{time.Duration(45) * time.Second, 45},
+}
+
+var quantileTests = []struct {
+	runtimes   RuntimeDistribution
+	confidence float64
+	quantile   time.Duration
+	overrun    float64
+}{
+	{longTail, 0.95, time.Duration(45) * time.Second, 0.05},
+	{longTail, 1, time.Duration(300) * time.Second, 0},
+	{longTail, 0.01, time.Duration(45) * time.Second, 0.05},
+	{histogram, 0.5, time.Duration(30) * time.Second, 0.5},
+	{histogram, 0.95, time.Duration(45) * time.Second, 0.05},
+	{histogram, 0.96, time.Duration(120) * time.Second, 0},
+
+	// Sub-second runtimes
+	{RuntimeSamples{time.Duration(250) * time.Millisecond, time.Duration(400) * time.Millisecond}, 0.5, time.Duration(250) * time.Millisecond, 0.5},
+}
+
+func Test_RuntimeDistribution(t *testing.T) {
+	for k, tt := range quantileTests {
+		q := tt.runtimes.Quantile(tt.confidence)
+		if q != tt.quantile {
+			t.Errorf("Error test: %d Quantile exp: %s got: %s", k, tt.quantile, q)
+		}
+		if o := tt.runtimes.Overrun(q); o != tt.overrun {
+			t.Errorf("Error test: %d Overrun exp: %f got: %f", k, tt.overrun, o)
+		}
+	}
+}
+
+func Test_FixedDurationDistribution(t *testing.T) {
+	p, err := FixedDurationDistribution(100, 10, longTail, 0.95, time.Duration(15)*time.Second, time.Duration(60)*time.Second)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	comparePulse(t, 0, p, &Pulse{Volume: 10, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second})
+	if p.OverrunRate != 0.05 {
+		t.Errorf("OverrunRate exp: %f got: %f", 0.05, p.OverrunRate)
+	}
+
+	// Sizing for every job stretches the frequency over the whole period
+	p, err = FixedDurationDistribution(100, 10, longTail, 1, time.Duration(15)*time.Second, time.Duration(600)*time.Second)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	comparePulse(t, 1, p, &Pulse{Volume: 10, Frequency: time.Duration(600) * time.Second, Duration: time.Duration(600) * time.Second})
+	if p.OverrunRate != 0 {
+		t.Errorf("OverrunRate exp: %f got: %f", 0.0, p.OverrunRate)
+	}
+}
+
+func Test_FixedConnectionsDistribution(t *testing.T) {
+	p, err := FixedConnectionsDistribution(10, 5, histogram, 0.95, time.Duration(15)*time.Second, 2)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	comparePulse(t, 0, p, &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(60) * time.Second})
+	if p.OverrunRate != 0.05 {
+		t.Errorf("OverrunRate exp: %f got: %f", 0.05, p.OverrunRate)
+	}
+}
+
+func Test_DistributionConfidenceErrors(t *testing.T) {
+	for _, confidence := range []float64{0, -0.5, 1.5} {
+		if _, err := FixedDurationDistribution(100, 10, longTail, confidence, time.Duration(15)*time.Second, time.Duration(60)*time.Second); err == nil {
+			t.Errorf("Expected error for confidence %f", confidence)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package crawlrate
+
+import (
+	"net"
+)
+
+// Prefix lengths of the subnets of IPv4 and IPv6 proxies when none are given.
+const (
+	DefaultV4Prefix = 24
+	DefaultV6Prefix = 48
+)
+
+// SubnetLimit limits the connections made through the proxies of any one subnet in
+// each tick, as search engines throttle by subnet as well as by address. A proxy given
+// by hostname is a subnet of its own.
+type SubnetLimit struct {
+	V4Prefix int // Prefix length of the subnet of an IPv4 proxy, or 0 for DefaultV4Prefix
+	V6Prefix int // Prefix length of the subnet of an IPv6 proxy, or 0 for DefaultV6Prefix
+	Max      int // Connections through the proxies of a subnet in a tick, or 0 for no limit
+}
+
+// WithSubnetLimit holds each subnet of the proxies in the crawl plan to s.Max
+// connections in each tick.
+//
+// The connections a subnet is allowed in a tick are shared one at a time between its
+// proxies, starting from a different proxy each tick, so the work spreads across the
+// proxies of the subnet over the plan. The rows of the plan hold only the connections
+// the limit allows, so the work moves to the proxies of the other subnets or to later
+// ticks. Pass the same limit and the proxies to the pulse algorithm in PulseInputs so
+// the pulse allows for the limit.
+func WithSubnetLimit(s SubnetLimit) Option {
+	return func(o *options) {
+		o.subnets = s
+	}
+}
+
+func (s SubnetLimit) validate() error {
+	if s.Max < 0 || s.V4Prefix < 0 || s.V4Prefix > 32 || s.V6Prefix < 0 || s.V6Prefix > 128 {
+		return &InputError{"subnet limit", s, ErrInvalidSubnet}
+	}
+	return nil
+}
+
+// prefixes returns the prefix lengths of the subnets of IPv4 and IPv6 proxies.
+func (s SubnetLimit) prefixes() (v4, v6 int) {
+	v4, v6 = s.V4Prefix, s.V6Prefix
+	if v4 == 0 {
+		v4 = DefaultV4Prefix
+	}
+	if v6 == 0 {
+		v6 = DefaultV6Prefix
+	}
+	return v4, v6
+}
+
+// groups returns the indexes of the proxies in each subnet, in the order the subnets
+// first appear.
+func (s SubnetLimit) groups(proxies []Proxy) [][]int {
+	v4Prefix, v6Prefix := s.prefixes()
+	var groups [][]int
+	index := make(map[string]int)
+	for i, p := range proxies {
+		key := "host:" + p.Host
+		if ip := p.IP(); ip != nil {
+			if v4 := ip.To4(); v4 != nil {
+				key = v4.Mask(net.CIDRMask(v4Prefix, 32)).String()
+			} else {
+				key = ip.Mask(net.CIDRMask(v6Prefix, 128)).String()
+			}
+		}
+
+		g, ok := index[key]
+		if !ok {
+			g = len(groups)
+			index[key] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// subnets is a subnet limit applied to the proxies of a plan.
+type subnets struct {
+	SubnetLimit
+	groups [][]int // Indexes of the proxies in each subnet
+}
+
+// of returns the limit applied to the proxies.
+func (s SubnetLimit) of(proxies []Proxy) subnets {
+	if s.Max == 0 {
+		return subnets{}
+	}
+	return subnets{s, s.groups(proxies)}
+}
+
+// limit returns the number of connections available to each proxy in a row once
+// each subnet is held to its limit, given the tick of the row.
+func (s subnets) limit(row []int, tick int) []int {
+	if s.Max == 0 {
+		return row
+	}
+
+	out := make([]int, len(row))
+	copy(out, row)
+	for _, proxies := range s.groups {
+		var total int
+		for _, proxy := range proxies {
+			total += row[proxy]
+		}
+		if total <= s.Max {
+			continue
+		}
+
+		// Deal the connections out a proxy at a time, starting from the
+		// proxy after the one that started the last tick.
+		for _, proxy := range proxies {
+			out[proxy] = 0
+		}
+		start := tick % len(proxies)
+		for left := s.Max; left > 0; {
+			for i := range proxies {
+				proxy := proxies[(start+i)%len(proxies)]
+				if left > 0 && out[proxy] < row[proxy] {
+					out[proxy]++
+					left--
+				}
+			}
+		}
+	}
+	return out
+}
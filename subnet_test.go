@@ -0,0 +1,132 @@
+package crawlrate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+var subnetLimitTests = []struct {
+	max  int
+	tick int
+	row  []int
+	out  []int
+}{
+	{0, 0, []int{2, 2, 2, 2}, []int{2, 2, 2, 2}},
+	{6, 0, []int{2, 2, 2, 2}, []int{2, 2, 2, 2}},
+	{3, 0, []int{2, 2, 2, 2}, []int{1, 1, 1, 2}},
+	{2, 1, []int{2, 2, 2, 2}, []int{0, 1, 1, 2}},
+	{4, 2, []int{2, 2, 2, 2}, []int{1, 1, 2, 2}},
+	{1, 0, []int{2, 2, 2, 2}, []int{1, 0, 0, 1}},
+	{2, 0, []int{0, 3, 1, 0}, []int{0, 1, 1, 0}},
+}
+
+func Test_SubnetLimitRow(t *testing.T) {
+	groups := [][]int{{0, 1, 2}, {3}}
+	for k, tt := range subnetLimitTests {
+		out := subnets{SubnetLimit{24, 48, tt.max}, groups}.limit(tt.row, tt.tick)
+		if fmt.Sprint(out) != fmt.Sprint(tt.out) {
+			t.Errorf("Error test: %d exp: %v got: %v\n", k, tt.out, out)
+		}
+	}
+}
+
+func Test_SubnetGroups(t *testing.T) {
+	proxies := []Proxy{
+		{Host: "2001:db8:1::1"}, {Host: "2001:db8:1:2::1"}, {Host: "2001:db8:2::1"},
+		{Host: "10.0.0.1"}, {Host: "10.0.0.200", Port: 3128}, {Host: "10.0.1.1"},
+		{Host: "proxy-1.example"}, {Host: "proxy-2.example"},
+	}
+	out := [][]int{{0, 1}, {2}, {3, 4}, {5}, {6}, {7}}
+
+	groups := SubnetLimit{24, 48, 1}.groups(proxies)
+	if fmt.Sprint(groups) != fmt.Sprint(out) {
+		t.Errorf("Expected %v. Got: %v\n", out, groups)
+	}
+
+	// Prefixes left unset are a /24 and a /48, never a /0
+	if groups := (SubnetLimit{Max: 1}).groups(proxies); fmt.Sprint(groups) != fmt.Sprint(out) {
+		t.Errorf("Expected %v. Got: %v\n", out, groups)
+	}
+}
+
+func Test_SubnetLimit(t *testing.T) {
+	proxies := append(generateProxies("10.0.0.", 3), generateProxies("10.0.1.", 1)...)
+	limit := SubnetLimit{24, 48, 2}
+
+	// Each tick holds 2 connections of each subnet rather than 8, so the pulse
+	// takes 4 ticks rather than 2
+	algorithm, _ := Lookup("connections")
+	in := PulseInputs{KeywordCount: 15, ProxyCount: 4, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, ConnectionCount: 2, SubnetLimit: limit, Proxies: proxies}
+	p, err := algorithm.Pulse(in)
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+	comparePulse(t, 0, p, &Pulse{Volume: 2, Frequency: minute, Duration: time.Duration(240) * time.Second})
+
+	// Within a time period, the proxies of the subnet left room by the limit are
+	// given more connections instead
+	duration, _ := Lookup("duration")
+	din := PulseInputs{KeywordCount: 20, ProxyCount: 4, AvgJobRuntime: time.Duration(45) * time.Second, MinimumDelay: time.Duration(15) * time.Second, TimePeriod: time.Duration(300) * time.Second, SubnetLimit: limit, Proxies: proxies}
+	if dp, err := duration.Pulse(din); err != nil {
+		t.Errorf("Error: %s\n", err)
+	} else {
+		comparePulse(t, 1, dp, &Pulse{Volume: 2, Frequency: minute, Duration: time.Duration(300) * time.Second})
+	}
+
+	cp, err := New(generateKeywords("keyword-", 15), proxies, p, WithSubnetLimit(limit))
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+	if len(cp) != 15 {
+		t.Errorf("Expected 15 rules. Got: %d\n", len(cp))
+	}
+
+	subnets := make(map[string]int)
+	used := make(map[string]bool)
+	for _, r := range cp {
+		subnets[fmt.Sprintf("%s/%s", r.Time, r.Proxy.IP().Mask(net.CIDRMask(24, 32)))]++
+		used[r.Proxy.Host] = true
+	}
+	for subnet, n := range subnets {
+		if n > 2 {
+			t.Errorf("Expected no more than 2 connections in %s. Got: %d\n", subnet, n)
+		}
+	}
+	if len(used) != len(proxies) {
+		t.Errorf("Expected every proxy to be used. Got: %v\n", used)
+	}
+
+	if _, err := New(generateKeywords("keyword-", 1), proxies, p, WithSubnetLimit(SubnetLimit{33, 48, 1})); !errors.Is(err, ErrInvalidSubnet) {
+		t.Errorf("Expected %v. Got: %v\n", ErrInvalidSubnet, err)
+	}
+	in.Proxies = proxies[:3]
+	if _, err := algorithm.Pulse(in); !errors.Is(err, ErrSubnetProxies) {
+		t.Errorf("Expected %v. Got: %v\n", ErrSubnetProxies, err)
+	}
+}
+
+func Test_SubnetLimitCooldown(t *testing.T) {
+	// Only the connection the limit leaves a proxy counts towards its rest, so
+	// the proxies of the subnet take it in turns
+	l := rowLimits{cooldown: Cooldown{Requests: 2, Rest: minute}, subnets: SubnetLimit{24, 48, 1}.of(generateProxies("10.0.0.", 2))}
+	grid := l.rowVolumes([]int{2, 2}, 6, minute)
+
+	out := [][]int{{1, 0}, {0, 1}, {1, 0}, {0, 1}, {1, 0}, {0, 1}}
+	if fmt.Sprint(grid) != fmt.Sprint(out) {
+		t.Errorf("Expected %v. Got: %v\n", out, grid)
+	}
+
+	p := &Pulse{Volume: 2, Frequency: minute, Duration: time.Duration(240) * time.Second}
+	cp, err := New(generateKeywords("keyword-", 4), generateProxies("10.0.0.", 2), p, WithCooldown(Cooldown{Requests: 2, Rest: minute}), WithSubnetLimit(SubnetLimit{24, 48, 1}))
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+	for n, r := range cp {
+		if exp := fmt.Sprintf("10.0.0.%d", n%2); r.Time != time.Duration(n)*minute || r.Proxy.Host != exp {
+			t.Errorf("Error test: %d exp: %s at %s got: %s", n, exp, time.Duration(n)*minute, r)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package crawlrate
+
+import (
+	"strings"
+)
+
+// Target is a search target a keyword is crawled against e.g. google.co.uk in
+// en-GB on mobile.
+type Target struct {
+	Engine string // Search engine e.g. google.co.uk
+	Locale string // Locale of the results e.g. en-GB
+	Device string // Device the results are for e.g. desktop or mobile
+}
+
+// String returns the target as engine/locale/device, leaving out the parts not set.
+func (t Target) String() string {
+	var parts []string
+	for _, p := range []string{t.Engine, t.Locale, t.Device} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// ParseTarget parses a target written as engine/locale/device e.g.
+// google.co.uk/en-GB/mobile. The locale and device may be left out.
+func ParseTarget(s string) (Target, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) > 3 || parts[0] == "" {
+		return Target{}, &InputError{"target", s, ErrInvalidTarget}
+	}
+
+	var t Target
+	t.Engine = parts[0]
+	if len(parts) > 1 {
+		t.Locale = parts[1]
+	}
+	if len(parts) > 2 {
+		t.Device = parts[2]
+	}
+	return t, nil
+}
+
+// WithTargets fans each keyword out across the targets, so that the keyword is
+// crawled once against each of them. Every crawl takes its own connection in the
+// plan. Keywords that already have a target are left as they are.
+func WithTargets(targets ...Target) Option {
+	return func(o *options) {
+		o.targets = targets
+	}
+}
+
+// ExpandTargets returns the keywords fanned out across the targets, as WithTargets
+// does when a plan is created. Keywords that already have a target are left as
+// they are. The number of keywords returned is the number of jobs a pulse must
+// have the capacity for.
+func ExpandTargets(keywords []Keyword, targets []Target) []Keyword {
+	if len(targets) == 0 {
+		return keywords
+	}
+
+	expanded := make([]Keyword, 0, len(keywords)*len(targets))
+	for _, k := range keywords {
+		if k.Target != (Target{}) {
+			expanded = append(expanded, k)
+			continue
+		}
+		for _, t := range targets {
+			k.Target = t
+			expanded = append(expanded, k)
+		}
+	}
+	return expanded
+}
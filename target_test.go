@@ -0,0 +1,82 @@
+package crawlrate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	googleUK     = Target{Engine: "google.co.uk", Locale: "en-GB", Device: "desktop"}
+	googleMobile = Target{Engine: "google.com", Locale: "en-US", Device: "mobile"}
+)
+
+var parseTargetTests = []struct {
+	in  string
+	out Target
+	err error
+}{
+	{"google.co.uk/en-GB/desktop", googleUK, nil},
+	{"google.com", Target{Engine: "google.com"}, nil},
+	{"google.com/en-US", Target{Engine: "google.com", Locale: "en-US"}, nil},
+	{"", Target{}, ErrInvalidTarget},
+	{"/en-GB", Target{}, ErrInvalidTarget},
+	{"google.com/en-US/mobile/extra", Target{}, ErrInvalidTarget},
+}
+
+func Test_ParseTarget(t *testing.T) {
+	for k, tt := range parseTargetTests {
+		target, err := ParseTarget(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Error test: %d err exp: %v got: %v\n", k, tt.err, err)
+		}
+		if target != tt.out {
+			t.Errorf("Error test: %d exp: %+v got: %+v\n", k, tt.out, target)
+		}
+		if err == nil && target.String() != tt.in {
+			t.Errorf("Error test: %d String exp: %s got: %s\n", k, tt.in, target.String())
+		}
+	}
+}
+
+func Test_ExpandTargets(t *testing.T) {
+	pinned := Keyword{Text: "pinned", Target: googleUK}
+	expanded := ExpandTargets([]Keyword{{Text: "a", Priority: 1}, pinned}, []Target{googleUK, googleMobile})
+
+	out := []Keyword{
+		{Text: "a", Priority: 1, Target: googleUK},
+		{Text: "a", Priority: 1, Target: googleMobile},
+		pinned,
+	}
+	if len(expanded) != len(out) {
+		t.Fatalf("Non-equal slice lengths. Got: %d Expected: %d\n", len(expanded), len(out))
+	}
+	for k := range out {
+		if expanded[k] != out[k] {
+			t.Errorf("Error test: %d exp: %+v got: %+v\n", k, out[k], expanded[k])
+		}
+	}
+}
+
+func Test_Targets(t *testing.T) {
+	// Each target takes its own connection
+	p := &Pulse{Volume: 2, Frequency: time.Duration(60) * time.Second, Duration: time.Duration(120) * time.Second}
+	cp, err := New(generateKeywords("keyword-", 2), generateProxies("127.0.0.", 1), p, WithTargets(googleUK, googleMobile))
+	if err != nil {
+		t.Fatalf("Error: %s\n", err)
+	}
+
+	comparePlan(t, 0, cp, CrawlPlan{
+		{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-0", googleUK},
+		{time.Duration(0) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-0", googleMobile},
+		{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 0, "keyword-1", googleUK},
+		{time.Duration(60) * time.Second, Proxy{Host: "127.0.0.0"}, 1, "keyword-1", googleMobile},
+	})
+
+	// Targets count towards the capacity of the pulse
+	_, err = New(generateKeywords("keyword-", 3), generateProxies("127.0.0.", 1), p, WithTargets(googleUK, googleMobile))
+	var unscheduled *UnscheduledError
+	if !errors.As(err, &unscheduled) || len(unscheduled.Keywords) != 2 || unscheduled.Keywords[1].Target != googleMobile {
+		t.Errorf("Expected keyword-2 unscheduled on both targets. Got: %v\n", err)
+	}
+}